@@ -9,10 +9,14 @@ import (
 	"github.com/herhu/Microservices-PR/pkg/logger"
 )
 
+var (
+	configPath = flag.String("config", "", "API Gateway microservice config path")
+)
+
 func main() {
 	flag.Parse()
 
-	cfg, err := config.InitConfig()
+	cfg, err := config.InitConfig(*configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
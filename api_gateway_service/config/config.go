@@ -1,46 +1,55 @@
 package config
 
 import (
-	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/herhu/Microservices-PR/pkg/constants"
 	"github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/pkg/probes"
+	"github.com/herhu/Microservices-PR/pkg/redis"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	"github.com/pkg/errors"
 
 	"github.com/spf13/viper"
 )
 
-var configPath string
-
-func init() {
-	flag.StringVar(&configPath, "config", "", "API Gateway microservice config path")
-}
-
 type Config struct {
-	ServiceName string          `mapstructure:"serviceName"`
-	Logger      *logger.Config  `mapstructure:"logger"`
-	KafkaTopics KafkaTopics     `mapstructure:"kafkaTopics"`
-	Http        Http            `mapstructure:"http"`
-	Grpc        Grpc            `mapstructure:"grpc"`
-	Kafka       *kafka.Config   `mapstructure:"kafka"`
-	Probes      probes.Config   `mapstructure:"probes"`
-	Jaeger      *tracing.Config `mapstructure:"jaeger"`
+	ServiceName   string          `mapstructure:"serviceName"`
+	Logger        *logger.Config  `mapstructure:"logger"`
+	KafkaTopics   KafkaTopics     `mapstructure:"kafkaTopics"`
+	Http          Http            `mapstructure:"http"`
+	Grpc          Grpc            `mapstructure:"grpc"`
+	Kafka         *kafka.Config   `mapstructure:"kafka"`
+	Redis         *redis.Config   `mapstructure:"redis"`
+	Probes        probes.Config   `mapstructure:"probes"`
+	Jaeger        *tracing.Config `mapstructure:"jaeger"`
+	CommandStatus CommandStatus   `mapstructure:"commandStatus"`
+	Auth          Auth            `mapstructure:"auth"`
+	RateLimit     RateLimit       `mapstructure:"rateLimit"`
+	Idempotency   Idempotency     `mapstructure:"idempotency"`
 }
 
 type Http struct {
-	Port                string   `mapstructure:"port"`
-	Development         bool     `mapstructure:"development"`
-	BasePath            string   `mapstructure:"basePath"`
-	ProductsPath        string   `mapstructure:"productsPath"`
-	DebugHeaders        bool     `mapstructure:"debugHeaders"`
-	HttpClientDebug     bool     `mapstructure:"httpClientDebug"`
-	DebugErrorsResponse bool     `mapstructure:"debugErrorsResponse"`
-	IgnoreLogUrls       []string `mapstructure:"ignoreLogUrls"`
+	Port                  string        `mapstructure:"port"`
+	Development           bool          `mapstructure:"development"`
+	BasePath              string        `mapstructure:"basePath"`
+	ProductsPath          string        `mapstructure:"productsPath"`
+	CommandsPath          string        `mapstructure:"commandsPath"`
+	DebugHeaders          bool          `mapstructure:"debugHeaders"`
+	HttpClientDebug       bool          `mapstructure:"httpClientDebug"`
+	DebugErrorsResponse   bool          `mapstructure:"debugErrorsResponse"`
+	IgnoreLogUrls         []string      `mapstructure:"ignoreLogUrls"`
+	StreamHeartbeat       time.Duration `mapstructure:"streamHeartbeat"`
+	StreamBufferSize      int           `mapstructure:"streamBufferSize"`
+	MaxStreamConnections  int           `mapstructure:"maxStreamConnections"`
+	StreamHistorySize     int           `mapstructure:"streamHistorySize"`
+	ProjectionWaitTimeout time.Duration `mapstructure:"projectionWaitTimeout"`
+	TrustedProxies        []string      `mapstructure:"trustedProxies"`
+	StreamAllowedOrigins  []string      `mapstructure:"streamAllowedOrigins"`
 }
 
 type Grpc struct {
@@ -51,9 +60,61 @@ type KafkaTopics struct {
 	ProductCreate kafka.TopicConfig `mapstructure:"productCreate"`
 	ProductUpdate kafka.TopicConfig `mapstructure:"productUpdate"`
 	ProductDelete kafka.TopicConfig `mapstructure:"productDelete"`
+	CommandResult kafka.TopicConfig `mapstructure:"commandResult"`
+}
+
+// CommandStatus redis storage of command statuses reported by writer_service
+type CommandStatus struct {
+	RedisPrefixKey string        `mapstructure:"redisPrefixKey"`
+	TTL            time.Duration `mapstructure:"ttl"`
 }
 
-func InitConfig() (*Config, error) {
+// Auth bearer token validation, RS256 tokens are verified with the jwks file key of their kid, tokens without kid
+// only when AllowMissingKid is set and the file has a single key. HS256 tokens are only accepted when EnableHs256
+// is set and are verified with the hmac secret, which has no default.
+type Auth struct {
+	Enable          bool          `mapstructure:"enable"`
+	EnableHs256     bool          `mapstructure:"enableHs256"`
+	HmacSecret      string        `mapstructure:"hmacSecret"`
+	JwksPath        string        `mapstructure:"jwksPath"`
+	AllowMissingKid bool          `mapstructure:"allowMissingKid"`
+	Issuer          string        `mapstructure:"issuer"`
+	Audience        string        `mapstructure:"audience"`
+	RolesClaim      string        `mapstructure:"rolesClaim"`
+	Leeway          time.Duration `mapstructure:"leeway"`
+}
+
+// RateLimit redis token buckets shared by the gateway instances. Clients are identified by a known api key,
+// the token subject or the ip, routes without a rule use the default rule.
+type RateLimit struct {
+	Enable         bool            `mapstructure:"enable"`
+	RedisPrefixKey string          `mapstructure:"redisPrefixKey"`
+	ApiKeyHeader   string          `mapstructure:"apiKeyHeader"`
+	ApiKeys        []string        `mapstructure:"apiKeys"`
+	Default        RateLimitRule   `mapstructure:"default"`
+	Routes         []RateLimitRule `mapstructure:"routes"`
+}
+
+// RateLimitRule allows Limit requests per Window with bursts up to Limit, a zero limit is unlimited.
+// Path is the echo route path, e.g. /api/v1/products/:id
+type RateLimitRule struct {
+	Method string        `mapstructure:"method"`
+	Path   string        `mapstructure:"path"`
+	Limit  int           `mapstructure:"limit"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+// Idempotency responses of write requests with an Idempotency-Key are replayed for TTL,
+// LockTTL bounds how long a request in progress holds its key
+type Idempotency struct {
+	Enable         bool          `mapstructure:"enable"`
+	RedisPrefixKey string        `mapstructure:"redisPrefixKey"`
+	TTL            time.Duration `mapstructure:"ttl"`
+	LockTTL        time.Duration `mapstructure:"lockTtl"`
+}
+
+// InitConfig loads the config file, an empty path falls back to the config path env and the default config
+func InitConfig(configPath string) (*Config, error) {
 	if configPath == "" {
 		configPathFromEnv := os.Getenv(constants.ConfigPath)
 		if configPathFromEnv != "" {
@@ -88,10 +149,34 @@ func InitConfig() (*Config, error) {
 	if kafkaBrokers != "" {
 		cfg.Kafka.Brokers = []string{kafkaBrokers}
 	}
+	kafkaDriver := os.Getenv(constants.KafkaDriver)
+	if kafkaDriver != "" {
+		cfg.Kafka.Driver = kafkaDriver
+	}
 	jaegerAddr := os.Getenv(constants.JaegerHostPort)
 	if jaegerAddr != "" {
 		cfg.Jaeger.HostPort = jaegerAddr
 	}
+	redisAddr := os.Getenv(constants.RedisAddr)
+	if redisAddr != "" {
+		cfg.Redis.Addr = redisAddr
+	}
+	jwtEnableHs256 := os.Getenv(constants.JwtEnableHs256)
+	if jwtEnableHs256 != "" {
+		enableHs256, err := strconv.ParseBool(jwtEnableHs256)
+		if err != nil {
+			return nil, errors.Wrap(err, "strconv.ParseBool")
+		}
+		cfg.Auth.EnableHs256 = enableHs256
+	}
+	jwtHmacSecret := os.Getenv(constants.JwtHmacSecret)
+	if jwtHmacSecret != "" {
+		cfg.Auth.HmacSecret = jwtHmacSecret
+	}
+	jwtJwksPath := os.Getenv(constants.JwtJwksPath)
+	if jwtJwksPath != "" {
+		cfg.Auth.JwksPath = jwtJwksPath
+	}
 	readerServicePort := os.Getenv(constants.ReaderServicePort)
 	if readerServicePort != "" {
 		cfg.Grpc.ReaderServicePort = readerServicePort
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/pkg/identity"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRolesClaim = "roles"
+	defaultLeeway     = 30 * time.Second
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+	ErrNoKeys       = errors.New("auth is enabled without HS256 or jwks keys")
+	ErrNoHmacSecret = errors.New("HS256 is enabled without hmac secret")
+)
+
+type TokenValidator interface {
+	Validate(token string) (*identity.Identity, error)
+}
+
+type jwtValidator struct {
+	cfg        *config.Config
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	parser     *jwt.Parser
+}
+
+// NewJwtValidator loads the jwks file, tokens are only accepted for the algorithms a key is configured for
+// and HS256 only when it is enabled
+func NewJwtValidator(cfg *config.Config) (*jwtValidator, error) {
+	rsaKeys := make(map[string]*rsa.PublicKey)
+	if cfg.Auth.JwksPath != "" {
+		keys, err := LoadJwks(cfg.Auth.JwksPath)
+		if err != nil {
+			return nil, err
+		}
+		rsaKeys = keys
+	}
+
+	validMethods := []string{jwt.SigningMethodRS256.Alg()}
+	var hmacSecret []byte
+	if cfg.Auth.EnableHs256 {
+		if cfg.Auth.HmacSecret == "" {
+			return nil, ErrNoHmacSecret
+		}
+		hmacSecret = []byte(cfg.Auth.HmacSecret)
+		validMethods = append(validMethods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if len(hmacSecret) == 0 && len(rsaKeys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	return &jwtValidator{
+		cfg:        cfg,
+		hmacSecret: hmacSecret,
+		rsaKeys:    rsaKeys,
+		parser: &jwt.Parser{
+			ValidMethods:         validMethods,
+			SkipClaimsValidation: true,
+		},
+	}, nil
+}
+
+// Validate verifies the signature and the time, issuer and audience claims with the configured leeway,
+// the identity is the sub claim with the roles claim
+func (v *jwtValidator) Validate(token string) (*identity.Identity, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+
+	now := time.Now()
+	if !claims.VerifyExpiresAt(now.Add(-v.leeway()).Unix(), true) {
+		return nil, ErrTokenExpired
+	}
+	if !claims.VerifyNotBefore(now.Add(v.leeway()).Unix(), false) {
+		return nil, errors.Wrap(ErrInvalidToken, "token used before nbf")
+	}
+	if v.cfg.Auth.Issuer != "" && !claims.VerifyIssuer(v.cfg.Auth.Issuer, true) {
+		return nil, errors.Wrap(ErrInvalidToken, "unexpected iss")
+	}
+	if v.cfg.Auth.Audience != "" && !claims.VerifyAudience(v.cfg.Auth.Audience, true) {
+		return nil, errors.Wrap(ErrInvalidToken, "unexpected aud")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.Wrap(ErrInvalidToken, "missing sub")
+	}
+
+	return &identity.Identity{Subject: subject, Roles: v.roles(claims)}, nil
+}
+
+func (v *jwtValidator) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if len(v.hmacSecret) == 0 {
+			return nil, errors.New("HS256 tokens are not accepted")
+		}
+		return v.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return v.keyWithoutKid()
+		}
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		return nil, errors.Errorf("unknown key id %q", kid)
+	}
+	return nil, errors.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// keyWithoutKid returns the only jwks key when tokens without kid are allowed, a token must name one of several keys
+func (v *jwtValidator) keyWithoutKid() (interface{}, error) {
+	if v.cfg.Auth.AllowMissingKid && len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key, nil
+		}
+	}
+	return nil, errors.New("missing key id")
+}
+
+// roles accepts a list or a space separated string
+func (v *jwtValidator) roles(claims jwt.MapClaims) []string {
+	switch value := claims[v.rolesClaim()].(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		roles := make([]string, 0, len(value))
+		for _, role := range value {
+			if r, ok := role.(string); ok && r != "" {
+				roles = append(roles, r)
+			}
+		}
+		return roles
+	}
+	return nil
+}
+
+func (v *jwtValidator) rolesClaim() string {
+	if v.cfg.Auth.RolesClaim != "" {
+		return v.cfg.Auth.RolesClaim
+	}
+	return defaultRolesClaim
+}
+
+func (v *jwtValidator) leeway() time.Duration {
+	if v.cfg.Auth.Leeway > 0 {
+		return v.cfg.Auth.Leeway
+	}
+	return defaultLeeway
+}
@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	testKid        = "test-key"
+	testHmacSecret = "test-secret"
+	testIssuer     = "https://issuer.test"
+	testAudience   = "catalog"
+)
+
+// writeTestJwks writes the public keys to a jwks file by key id
+func writeTestJwks(t *testing.T, keys map[string]*rsa.PrivateKey) string {
+	t.Helper()
+
+	set := jwks{}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+	return path
+}
+
+func newTestRsaKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	return key
+}
+
+func testClaims(now time.Time) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"roles": []string{"catalog:read", "catalog:write"},
+	}
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func signNone(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func withClaims(claims jwt.MapClaims, changes jwt.MapClaims) jwt.MapClaims {
+	changed := jwt.MapClaims{}
+	for name, value := range claims {
+		changed[name] = value
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(changed, name)
+			continue
+		}
+		changed[name] = value
+	}
+	return changed
+}
+
+func TestJwtValidator_Validate(t *testing.T) {
+	key := newTestRsaKey(t)
+	otherKey := newTestRsaKey(t)
+	jwksPath := writeTestJwks(t, map[string]*rsa.PrivateKey{testKid: key})
+	now := time.Now()
+	claims := testClaims(now)
+
+	rs256Only := config.Auth{JwksPath: jwksPath, Issuer: testIssuer, Audience: testAudience, Leeway: 30 * time.Second}
+	withHs256 := rs256Only
+	withHs256.EnableHs256 = true
+	withHs256.HmacSecret = testHmacSecret
+	missingKid := rs256Only
+	missingKid.AllowMissingKid = true
+
+	tests := []struct {
+		name    string
+		auth    config.Auth
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "RS256 token",
+			auth:  rs256Only,
+			token: signRS256(t, key, testKid, claims),
+		},
+		{
+			name:    "RS256 token of another key",
+			auth:    rs256Only,
+			token:   signRS256(t, otherKey, testKid, claims),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "RS256 token of an unknown kid",
+			auth:    rs256Only,
+			token:   signRS256(t, key, "other-key", claims),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "RS256 token without kid",
+			auth:    rs256Only,
+			token:   signRS256(t, key, "", claims),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:  "RS256 token without kid when allowed",
+			auth:  missingKid,
+			token: signRS256(t, key, "", claims),
+		},
+		{
+			name:    "HS256 token when HS256 is disabled",
+			auth:    rs256Only,
+			token:   signHS256(t, testHmacSecret, claims),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:  "HS256 token when HS256 is enabled",
+			auth:  withHs256,
+			token: signHS256(t, testHmacSecret, claims),
+		},
+		{
+			name:    "HS256 token of another secret",
+			auth:    withHs256,
+			token:   signHS256(t, "other-secret", claims),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "alg none",
+			auth:    withHs256,
+			token:   signNone(t, claims),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "unexpected issuer",
+			auth:    rs256Only,
+			token:   signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"iss": "https://other.test"})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "missing issuer",
+			auth:    rs256Only,
+			token:   signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"iss": nil})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "unexpected audience",
+			auth:    rs256Only,
+			token:   signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"aud": "billing"})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:  "audience list",
+			auth:  rs256Only,
+			token: signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"aud": []string{"billing", testAudience}})),
+		},
+		{
+			name:    "missing subject",
+			auth:    rs256Only,
+			token:   signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"sub": nil})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:  "expired within leeway",
+			auth:  rs256Only,
+			token: signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"exp": now.Add(-10 * time.Second).Unix()})),
+		},
+		{
+			name:    "expired beyond leeway",
+			auth:    rs256Only,
+			token:   signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "missing expiry",
+			auth:    rs256Only,
+			token:   signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"exp": nil})),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:  "not before within leeway",
+			auth:  rs256Only,
+			token: signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"nbf": now.Add(10 * time.Second).Unix()})),
+		},
+		{
+			name:    "not before beyond leeway",
+			auth:    rs256Only,
+			token:   signRS256(t, key, testKid, withClaims(claims, jwt.MapClaims{"nbf": now.Add(time.Minute).Unix()})),
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := NewJwtValidator(&config.Config{Auth: tt.auth})
+			if err != nil {
+				t.Fatalf("NewJwtValidator: %v", err)
+			}
+
+			id, err := validator.Validate(tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Validate error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if id.Subject != "user-1" {
+				t.Fatalf("subject = %q, want user-1", id.Subject)
+			}
+		})
+	}
+}
+
+func TestJwtValidator_MissingKidWithSeveralKeys(t *testing.T) {
+	key := newTestRsaKey(t)
+	jwksPath := writeTestJwks(t, map[string]*rsa.PrivateKey{testKid: key, "other-key": newTestRsaKey(t)})
+
+	validator, err := NewJwtValidator(&config.Config{Auth: config.Auth{JwksPath: jwksPath, AllowMissingKid: true}})
+	if err != nil {
+		t.Fatalf("NewJwtValidator: %v", err)
+	}
+
+	if _, err := validator.Validate(signRS256(t, key, "", testClaims(time.Now()))); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Validate error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestJwtValidator_Roles(t *testing.T) {
+	tests := []struct {
+		name       string
+		rolesClaim string
+		claims     jwt.MapClaims
+		want       []string
+	}{
+		{name: "list", claims: jwt.MapClaims{"roles": []string{"a", "", "b"}}, want: []string{"a", "b"}},
+		{name: "space separated", claims: jwt.MapClaims{"roles": "a  b"}, want: []string{"a", "b"}},
+		{name: "configured claim", rolesClaim: "scope", claims: jwt.MapClaims{"scope": "a", "roles": "b"}, want: []string{"a"}},
+		{name: "missing", claims: jwt.MapClaims{}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := config.Auth{EnableHs256: true, HmacSecret: testHmacSecret, RolesClaim: tt.rolesClaim}
+			validator, err := NewJwtValidator(&config.Config{Auth: auth})
+			if err != nil {
+				t.Fatalf("NewJwtValidator: %v", err)
+			}
+
+			claims := withClaims(testClaims(time.Now()), jwt.MapClaims{"roles": nil})
+			id, err := validator.Validate(signHS256(t, testHmacSecret, withClaims(claims, tt.claims)))
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if !reflect.DeepEqual(id.Roles, tt.want) {
+				t.Fatalf("roles = %v, want %v", id.Roles, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewJwtValidator_Keys(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    config.Auth
+		wantErr error
+	}{
+		{name: "no keys", auth: config.Auth{}, wantErr: ErrNoKeys},
+		{name: "hmac secret without HS256", auth: config.Auth{HmacSecret: testHmacSecret}, wantErr: ErrNoKeys},
+		{name: "HS256 without hmac secret", auth: config.Auth{EnableHs256: true}, wantErr: ErrNoHmacSecret},
+		{name: "HS256", auth: config.Auth{EnableHs256: true, HmacSecret: testHmacSecret}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewJwtValidator(&config.Config{Auth: tt.auth}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewJwtValidator error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
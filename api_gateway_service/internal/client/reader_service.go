@@ -6,6 +6,7 @@ import (
 
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/pkg/identity"
 	"github.com/herhu/Microservices-PR/pkg/interceptors"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -30,6 +31,8 @@ func NewReaderServiceConn(ctx context.Context, cfg *config.Config, im intercepto
 		grpc.WithUnaryInterceptor(im.ClientRequestLoggerInterceptor()),
 		grpc.WithInsecure(),
 		grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(opts...)),
+		grpc.WithChainUnaryInterceptor(identity.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(identity.StreamClientInterceptor()),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "grpc.DialContext")
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/metrics"
+	"github.com/herhu/Microservices-PR/pkg/constants"
+	httpErrors "github.com/herhu/Microservices-PR/pkg/http_errors"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	uuid "github.com/satori/go.uuid"
+)
+
+type commandsHandlers struct {
+	group   *echo.Group
+	log     logger.Logger
+	cfg     *config.Config
+	repo    repository.Repository
+	metrics *metrics.ApiGatewayMetrics
+}
+
+func NewCommandsHandlers(
+	group *echo.Group,
+	log logger.Logger,
+	cfg *config.Config,
+	repo repository.Repository,
+	metrics *metrics.ApiGatewayMetrics,
+) *commandsHandlers {
+	return &commandsHandlers{group: group, log: log, cfg: cfg, repo: repo, metrics: metrics}
+}
+
+// GetCommandStatus
+// @Tags Commands
+// @Summary Get command status
+// @Description Status of a product command by the id returned in the X-Command-ID header: pending, succeeded or failed with the error
+// @Accept json
+// @Produce json
+// @Param id path string true "Command ID"
+// @Success 200 {object} dto.CommandStatusResponse
+// @Failure 404 {object} httpErrors.RestError
+// @Router /commands/{id} [get]
+func (h *commandsHandlers) GetCommandStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.GetCommandStatusHttpRequests.Inc()
+
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "commandsHandlers.GetCommandStatus")
+		defer span.Finish()
+
+		commandUUID, err := uuid.FromString(c.Param(constants.ID))
+		if err != nil {
+			h.log.WarnMsg("uuid.FromString", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+
+		status, err := h.repo.GetCommandStatus(ctx, commandUUID.String())
+		if err != nil {
+			h.log.WarnMsg("GetCommandStatus", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, status)
+	}
+}
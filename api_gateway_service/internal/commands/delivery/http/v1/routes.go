@@ -0,0 +1,5 @@
+package v1
+
+func (h *commandsHandlers) MapRoutes() {
+	h.group.GET("/:id", h.GetCommandStatus())
+}
@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/metrics"
+	httpErrors "github.com/herhu/Microservices-PR/pkg/http_errors"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/tracing"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	PoolSize = 10
+)
+
+type commandResultProcessor struct {
+	log     logger.Logger
+	cfg     *config.Config
+	repo    repository.Repository
+	metrics *metrics.ApiGatewayMetrics
+}
+
+func NewCommandResultProcessor(log logger.Logger, cfg *config.Config, repo repository.Repository, metrics *metrics.ApiGatewayMetrics) *commandResultProcessor {
+	return &commandResultProcessor{log: log, cfg: cfg, repo: repo, metrics: metrics}
+}
+
+// ProcessMessage stores the command result, statuses are best effort so failed messages are logged and committed
+func (p *commandResultProcessor) ProcessMessage(ctx context.Context, r kafkaClient.Reader, m kafka.Message, workerID int) {
+	p.log.KafkaProcessMessage(m.Topic, m.Partition, string(m.Value), workerID, m.Offset, m.Time)
+	p.metrics.CommandResultKafkaMessages.Inc()
+
+	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "commandResultProcessor.ProcessMessage")
+	defer span.Finish()
+
+	if err := p.processCommandResult(ctx, m); err != nil {
+		p.log.WarnMsg("processCommandResult", err)
+	}
+
+	p.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
+	if err := r.CommitMessages(ctx, m); err != nil {
+		p.log.WarnMsg("commitMessage", err)
+	}
+}
+
+func (p *commandResultProcessor) processCommandResult(ctx context.Context, m kafka.Message) error {
+	var msg kafkaMessages.ProductCommandResult
+	if err := proto.Unmarshal(m.Value, &msg); err != nil {
+		return errors.Wrap(err, "proto.Unmarshal")
+	}
+
+	status, err := p.repo.GetCommandStatus(ctx, msg.GetCommandID())
+	if err != nil {
+		if !errors.Is(err, httpErrors.NotFound) {
+			return err
+		}
+		// the pending status expired or was never stored, keep the result anyway
+		status = dto.NewPendingCommandStatus(msg.GetCommandID(), msg.GetCommandType(), msg.GetProductID())
+	}
+
+	status.Complete(&msg)
+	return p.repo.PutCommandStatus(ctx, status)
+}
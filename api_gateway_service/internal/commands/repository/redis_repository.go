@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	httpErrors "github.com/herhu/Microservices-PR/pkg/http_errors"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	redisCommandPrefixKey   = "gateway:commands"
+	defaultCommandStatusTTL = 24 * time.Hour
+)
+
+type redisRepository struct {
+	log         logger.Logger
+	cfg         *config.Config
+	redisClient redis.UniversalClient
+}
+
+func NewRedisRepository(log logger.Logger, cfg *config.Config, redisClient redis.UniversalClient) *redisRepository {
+	return &redisRepository{log: log, cfg: cfg, redisClient: redisClient}
+}
+
+// PutCommandStatus stores the status, every update restarts its ttl
+func (r *redisRepository) PutCommandStatus(ctx context.Context, status *dto.CommandStatusResponse) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.PutCommandStatus")
+	defer span.Finish()
+
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	if err := r.redisClient.Set(ctx, r.key(status.CommandID), statusBytes, r.ttl()).Err(); err != nil {
+		return errors.Wrap(err, "redisClient.Set")
+	}
+	return nil
+}
+
+// GetCommandStatus returns httpErrors.NotFound for unknown and expired commands
+func (r *redisRepository) GetCommandStatus(ctx context.Context, commandID string) (*dto.CommandStatusResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.GetCommandStatus")
+	defer span.Finish()
+
+	statusBytes, err := r.redisClient.Get(ctx, r.key(commandID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.Wrapf(httpErrors.NotFound, "command %s", commandID)
+		}
+		return nil, errors.Wrap(err, "redisClient.Get")
+	}
+
+	var status dto.CommandStatusResponse
+	if err := json.Unmarshal(statusBytes, &status); err != nil {
+		return nil, errors.Wrap(err, "json.Unmarshal")
+	}
+	return &status, nil
+}
+
+func (r *redisRepository) key(commandID string) string {
+	prefix := r.cfg.CommandStatus.RedisPrefixKey
+	if prefix == "" {
+		prefix = redisCommandPrefixKey
+	}
+	return fmt.Sprintf("%s:%s", prefix, commandID)
+}
+
+func (r *redisRepository) ttl() time.Duration {
+	if r.cfg.CommandStatus.TTL > 0 {
+		return r.cfg.CommandStatus.TTL
+	}
+	return defaultCommandStatusTTL
+}
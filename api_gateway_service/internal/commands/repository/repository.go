@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+)
+
+// Repository command statuses shared by all gateway instances
+type Repository interface {
+	PutCommandStatus(ctx context.Context, status *dto.CommandStatusResponse) error
+	GetCommandStatus(ctx context.Context, commandID string) (*dto.CommandStatusResponse, error)
+}
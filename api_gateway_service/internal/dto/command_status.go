@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
+)
+
+const (
+	CommandPending   = "pending"
+	CommandSucceeded = "succeeded"
+	CommandFailed    = "failed"
+)
+
+// CommandStatusResponse status of a command published by the gateway, error is set for failed commands
+type CommandStatusResponse struct {
+	CommandID   string    `json:"commandId"`
+	CommandType string    `json:"commandType"`
+	ProductID   string    `json:"productId"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func NewPendingCommandStatus(commandID, commandType, productID string) *CommandStatusResponse {
+	now := time.Now().UTC()
+	return &CommandStatusResponse{
+		CommandID:   commandID,
+		CommandType: commandType,
+		ProductID:   productID,
+		Status:      CommandPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Complete applies the writer result, accepted commands succeeded and rejected ones failed with the reason
+func (s *CommandStatusResponse) Complete(result *kafkaMessages.ProductCommandResult) {
+	s.Status = CommandSucceeded
+	s.Error = ""
+	if result.GetStatus() == kafkaMessages.CommandRejected {
+		s.Status = CommandFailed
+		s.Error = result.GetReason()
+	}
+	s.UpdatedAt = result.GetOccurredAt().AsTime()
+}
@@ -11,4 +11,5 @@ type CreateProductDto struct {
 
 type CreateProductResponseDto struct {
 	ProductID uuid.UUID `json:"productId" validate:"required"`
+	CommandID uuid.UUID `json:"commandId"`
 }
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	uuid "github.com/satori/go.uuid"
+)
+
+type GetProductsByIdsDto struct {
+	ProductIDs []uuid.UUID `json:"productIds" validate:"required,min=1"`
+}
+
+// ProductsBatchResponse products in the requested order, ids without a product are listed in notFoundIds
+type ProductsBatchResponse struct {
+	Products    []*ProductResponse `json:"products"`
+	NotFoundIDs []string           `json:"notFoundIds"`
+}
+
+func ProductsBatchResponseFromGrpc(res *readerService.GetProductsByIdsRes) *ProductsBatchResponse {
+	list := make([]*ProductResponse, 0, len(res.GetProducts()))
+	for _, product := range res.GetProducts() {
+		list = append(list, ProductResponseFromGrpc(product))
+	}
+
+	notFoundIDs := res.GetNotFoundIDs()
+	if notFoundIDs == nil {
+		notFoundIDs = make([]string, 0)
+	}
+
+	return &ProductsBatchResponse{Products: list, NotFoundIDs: notFoundIDs}
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/search"
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ProductChangeFilter per connection filter of the product change stream, empty fields match every change
+type ProductChangeFilter struct {
+	ProductIDs []uuid.UUID `json:"productIds,omitempty"`
+	Search     string      `json:"search,omitempty"`
+
+	query *search.Query
+}
+
+// NewProductChangeFilterFromQueryParams parses ids as a comma separated list of product ids and text as a search query
+func NewProductChangeFilterFromQueryParams(ids, text string) (*ProductChangeFilter, error) {
+	query, err := search.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	f := &ProductChangeFilter{Search: text, query: query}
+
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		productUUID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, errors.Errorf("invalid ids %q, expected comma separated product ids", ids)
+		}
+		f.ProductIDs = append(f.ProductIDs, productUUID)
+	}
+
+	return f, nil
+}
+
+// Matches reports whether the change passes the filter, deletes carry no product so only the ids filter them
+func (f *ProductChangeFilter) Matches(change *ProductChangeResponse) bool {
+	if len(f.ProductIDs) > 0 {
+		found := false
+		for _, productID := range f.ProductIDs {
+			if productID.String() == change.ProductID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if change.Product == nil || f.query == nil || f.query.Empty() {
+		return true
+	}
+	return f.query.Matches(change.Product.Name, change.Product.Description, change.Product.Price)
+}
+
+// ProductChangeResponse a created, updated or deleted product, product is empty for deletes.
+// ResumeToken resumes the stream after this change, CorrelationID is the request id of the command and not exposed.
+type ProductChangeResponse struct {
+	Type          string           `json:"type"`
+	ProductID     string           `json:"productId"`
+	Version       int64            `json:"version"`
+	Product       *ProductResponse `json:"product,omitempty"`
+	OccurredAt    time.Time        `json:"occurredAt"`
+	ResumeToken   string           `json:"resumeToken"`
+	CorrelationID string           `json:"-"`
+}
+
+func ProductChangeResponseFromGrpc(change *readerService.ProductChange) *ProductChangeResponse {
+	res := &ProductChangeResponse{
+		Type:          change.GetType(),
+		ProductID:     change.GetProductID(),
+		Version:       change.GetVersion(),
+		OccurredAt:    change.GetOccurredAt().AsTime(),
+		ResumeToken:   change.GetResumeToken(),
+		CorrelationID: change.GetCorrelationID(),
+	}
+	if change.GetProduct() != nil {
+		res.Product = ProductResponseFromGrpc(change.GetProduct())
+	}
+	return res
+}
@@ -9,6 +9,7 @@ type ProductsListResponse struct {
 	Size       int64              `json:"size" bson:"size"`
 	HasMore    bool               `json:"hasMore" bson:"hasMore"`
 	Products   []*ProductResponse `json:"products" bson:"products"`
+	NextCursor string             `json:"nextCursor,omitempty" bson:"nextCursor,omitempty"`
 }
 
 func ProductsListResponseFromGrpc(listResponse *readerService.SearchRes) *ProductsListResponse {
@@ -24,5 +25,6 @@ func ProductsListResponseFromGrpc(listResponse *readerService.SearchRes) *Produc
 		Size:       listResponse.GetSize(),
 		HasMore:    listResponse.GetHasMore(),
 		Products:   list,
+		NextCursor: listResponse.GetNextCursor(),
 	}
 }
@@ -13,6 +13,7 @@ type ProductResponse struct {
 	Price       float64   `json:"price,omitempty"`
 	CreatedAt   time.Time `json:"createdAt,omitempty"`
 	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	Version     int64     `json:"version"`
 }
 
 func ProductResponseFromGrpc(product *readerService.Product) *ProductResponse {
@@ -23,5 +24,6 @@ func ProductResponseFromGrpc(product *readerService.Product) *ProductResponse {
 		Price:       product.GetPrice(),
 		CreatedAt:   product.GetCreatedAt().AsTime(),
 		UpdatedAt:   product.GetUpdatedAt().AsTime(),
+		Version:     product.GetVersion(),
 	}
 }
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SearchProductFilter price and date range filters of product search, nil prices and zero dates are open bounds
+type SearchProductFilter struct {
+	MinPrice    *float64  `json:"minPrice,omitempty"`
+	MaxPrice    *float64  `json:"maxPrice,omitempty"`
+	CreatedFrom time.Time `json:"createdFrom,omitempty"`
+	CreatedTo   time.Time `json:"createdTo,omitempty"`
+	UpdatedFrom time.Time `json:"updatedFrom,omitempty"`
+	UpdatedTo   time.Time `json:"updatedTo,omitempty"`
+}
+
+// NewSearchProductFilterFromQueryParams parses prices as numbers and dates as RFC3339, empty params are skipped
+func NewSearchProductFilterFromQueryParams(minPrice, maxPrice, createdFrom, createdTo, updatedFrom, updatedTo string) (*SearchProductFilter, error) {
+	f := &SearchProductFilter{}
+
+	prices := []struct {
+		name  string
+		value string
+		dest  **float64
+	}{
+		{name: "minPrice", value: minPrice, dest: &f.MinPrice},
+		{name: "maxPrice", value: maxPrice, dest: &f.MaxPrice},
+	}
+	for _, p := range prices {
+		if p.value == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(p.value, 64)
+		if err != nil || price < 0 {
+			return nil, errors.Errorf("invalid %s %q, expected a non negative number", p.name, p.value)
+		}
+		*p.dest = &price
+	}
+
+	dates := []struct {
+		name  string
+		value string
+		dest  *time.Time
+	}{
+		{name: "createdFrom", value: createdFrom, dest: &f.CreatedFrom},
+		{name: "createdTo", value: createdTo, dest: &f.CreatedTo},
+		{name: "updatedFrom", value: updatedFrom, dest: &f.UpdatedFrom},
+		{name: "updatedTo", value: updatedTo, dest: &f.UpdatedTo},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, d.value)
+		if err != nil {
+			return nil, errors.Errorf("invalid %s %q, expected an RFC3339 date", d.name, d.value)
+		}
+		*d.dest = date
+	}
+
+	return f, nil
+}
+
+// ToGrpc sets the filter on the search request
+func (f *SearchProductFilter) ToGrpc(req *readerService.SearchReq) {
+	req.MinPrice = f.MinPrice
+	req.MaxPrice = f.MaxPrice
+	req.CreatedFrom = timestampOrNil(f.CreatedFrom)
+	req.CreatedTo = timestampOrNil(f.CreatedTo)
+	req.UpdatedFrom = timestampOrNil(f.UpdatedFrom)
+	req.UpdatedTo = timestampOrNil(f.UpdatedTo)
+}
+
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -2,9 +2,11 @@ package dto
 
 import uuid "github.com/satori/go.uuid"
 
+// UpdateProductDto ExpectedVersion is the product version the client read, 0 disables the concurrency check
 type UpdateProductDto struct {
-	ProductID   uuid.UUID `json:"productId" validate:"required,gte=0,lte=255"`
-	Name        string    `json:"name" validate:"required,gte=0,lte=255"`
-	Description string    `json:"description" validate:"required,gte=0,lte=5000"`
-	Price       float64   `json:"price" validate:"required,gte=0"`
+	ProductID       uuid.UUID `json:"productId" validate:"required,gte=0,lte=255"`
+	Name            string    `json:"name" validate:"required,gte=0,lte=255"`
+	Description     string    `json:"description" validate:"required,gte=0,lte=5000"`
+	Price           float64   `json:"price" validate:"required,gte=0"`
+	ExpectedVersion int64     `json:"expectedVersion,omitempty" validate:"gte=0"`
 }
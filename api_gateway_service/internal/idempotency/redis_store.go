@@ -0,0 +1,154 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	redisIdempotencyPrefixKey = "gateway:idempotency"
+	defaultTTL                = 24 * time.Hour
+	defaultLockTTL            = time.Minute
+	reserveAttempts           = 2
+)
+
+// ErrReservationLost the reservation expired and the key was reserved by another request or left free
+var ErrReservationLost = errors.New("idempotency reservation lost")
+
+// completeScript replaces the reservation of the token with the response
+var completeScript = redis.NewScript(`
+local stored = redis.call('GET', KEYS[1])
+if not stored or cjson.decode(stored).token ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// releaseScript deletes the reservation of the token
+var releaseScript = redis.NewScript(`
+local stored = redis.call('GET', KEYS[1])
+if not stored or cjson.decode(stored).token ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+type redisStore struct {
+	log         logger.Logger
+	cfg         *config.Config
+	redisClient redis.UniversalClient
+}
+
+func NewRedisStore(log logger.Logger, cfg *config.Config, redisClient redis.UniversalClient) *redisStore {
+	return &redisStore{log: log, cfg: cfg, redisClient: redisClient}
+}
+
+// Reserve sets the in progress response only when the key is free, the reservation expires after the lock ttl
+// so a gateway stopped mid request does not hold the key for the whole ttl
+func (s *redisStore) Reserve(ctx context.Context, key string, fingerprint string) (*Response, string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisStore.Reserve")
+	defer span.Finish()
+
+	token := uuid.NewV4().String()
+	reservation, err := json.Marshal(&Response{Fingerprint: fingerprint, Token: token})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "json.Marshal")
+	}
+
+	for attempt := 0; attempt < reserveAttempts; attempt++ {
+		reserved, err := s.redisClient.SetNX(ctx, s.key(key), reservation, s.lockTTL()).Result()
+		if err != nil {
+			return nil, "", errors.Wrap(err, "redisClient.SetNX")
+		}
+		if reserved {
+			return nil, token, nil
+		}
+
+		responseBytes, err := s.redisClient.Get(ctx, s.key(key)).Bytes()
+		if err != nil {
+			if err == redis.Nil {
+				continue
+			}
+			return nil, "", errors.Wrap(err, "redisClient.Get")
+		}
+
+		var response Response
+		if err := json.Unmarshal(responseBytes, &response); err != nil {
+			return nil, "", errors.Wrap(err, "json.Unmarshal")
+		}
+		response.Token = ""
+		return &response, "", nil
+	}
+
+	return nil, "", errors.Errorf("idempotency key %s expired while reserving", key)
+}
+
+// Complete replaces the reservation of the token with the response for the ttl,
+// returns ErrReservationLost when the reservation expired first
+func (s *redisStore) Complete(ctx context.Context, key string, token string, response *Response) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisStore.Complete")
+	defer span.Finish()
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	completed, err := completeScript.Run(ctx, s.redisClient, []string{s.key(key)}, token, responseBytes, s.ttl().Milliseconds()).Int()
+	if err != nil {
+		return errors.Wrap(err, "completeScript.Run")
+	}
+	if completed == 0 {
+		return ErrReservationLost
+	}
+	return nil
+}
+
+// Release frees the key of the token reservation so the request can be retried,
+// returns ErrReservationLost when the reservation expired first
+func (s *redisStore) Release(ctx context.Context, key string, token string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisStore.Release")
+	defer span.Finish()
+
+	released, err := releaseScript.Run(ctx, s.redisClient, []string{s.key(key)}, token).Int()
+	if err != nil {
+		return errors.Wrap(err, "releaseScript.Run")
+	}
+	if released == 0 {
+		return ErrReservationLost
+	}
+	return nil
+}
+
+func (s *redisStore) key(key string) string {
+	prefix := redisIdempotencyPrefixKey
+	if s.cfg.Idempotency.RedisPrefixKey != "" {
+		prefix = s.cfg.Idempotency.RedisPrefixKey
+	}
+	return fmt.Sprintf("%s:%s", prefix, key)
+}
+
+func (s *redisStore) ttl() time.Duration {
+	if s.cfg.Idempotency.TTL > 0 {
+		return s.cfg.Idempotency.TTL
+	}
+	return defaultTTL
+}
+
+func (s *redisStore) lockTTL() time.Duration {
+	if s.cfg.Idempotency.LockTTL > 0 {
+		return s.cfg.Idempotency.LockTTL
+	}
+	return defaultLockTTL
+}
@@ -0,0 +1,25 @@
+package idempotency
+
+import "context"
+
+// Response stored under an idempotency key, a response without status is a request still in progress
+type Response struct {
+	Fingerprint string            `json:"fingerprint"`
+	Token       string            `json:"token,omitempty"`
+	StatusCode  int               `json:"statusCode,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+func (r *Response) Completed() bool {
+	return r.StatusCode != 0
+}
+
+type Store interface {
+	// Reserve holds the key for a request with the fingerprint and returns the reservation token,
+	// returns the stored response without token when the key is taken
+	Reserve(ctx context.Context, key string, fingerprint string) (*Response, string, error)
+	// Complete and Release only apply while the key holds the reservation of the token
+	Complete(ctx context.Context, key string, token string, response *Response) error
+	Release(ctx context.Context, key string, token string) error
+}
@@ -9,13 +9,28 @@ import (
 )
 
 type ApiGatewayMetrics struct {
-	SuccessHttpRequests        prometheus.Counter
-	ErrorHttpRequests          prometheus.Counter
-	CreateProductHttpRequests  prometheus.Counter
-	UpdateProductHttpRequests  prometheus.Counter
-	DeleteProductHttpRequests  prometheus.Counter
-	GetProductByIdHttpRequests prometheus.Counter
-	SearchProductHttpRequests  prometheus.Counter
+	SuccessHttpRequests          prometheus.Counter
+	ErrorHttpRequests            prometheus.Counter
+	CreateProductHttpRequests    prometheus.Counter
+	UpdateProductHttpRequests    prometheus.Counter
+	DeleteProductHttpRequests    prometheus.Counter
+	GetProductByIdHttpRequests   prometheus.Counter
+	GetProductsByIdsHttpRequests prometheus.Counter
+	SearchProductHttpRequests    prometheus.Counter
+	StreamProductsHttpRequests   prometheus.Counter
+	StreamProductsWsRequests     prometheus.Counter
+	ActiveSseConnections         prometheus.Gauge
+	ActiveWsConnections          prometheus.Gauge
+	RejectedStreamConnections    prometheus.Counter
+	SlowStreamConsumers          prometheus.Counter
+	ProjectionWaitsConfirmed     prometheus.Counter
+	ProjectionWaitsPending       prometheus.Counter
+	GetCommandStatusHttpRequests prometheus.Counter
+	CommandResultKafkaMessages   prometheus.Counter
+	ThrottledHttpRequests        prometheus.Counter
+	RateLimiterErrors            prometheus.Counter
+	IdempotentReplays            prometheus.Counter
+	IdempotencyKeyMismatches     prometheus.Counter
 }
 
 func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
@@ -44,9 +59,69 @@ func NewApiGatewayMetrics(cfg *config.Config) *ApiGatewayMetrics {
 			Name: fmt.Sprintf("%s_get_product_by_id_http_requests_total", cfg.ServiceName),
 			Help: "The total number of get product by id http requests",
 		}),
+		GetProductsByIdsHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_products_by_ids_http_requests_total", cfg.ServiceName),
+			Help: "The total number of get products by ids http requests",
+		}),
 		SearchProductHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_search_product_http_requests_total", cfg.ServiceName),
 			Help: "The total number of search product http requests",
 		}),
+		StreamProductsHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_stream_products_http_requests_total", cfg.ServiceName),
+			Help: "The total number of product change server sent events requests",
+		}),
+		StreamProductsWsRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_stream_products_ws_requests_total", cfg.ServiceName),
+			Help: "The total number of product change websocket requests",
+		}),
+		ActiveSseConnections: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("%s_active_sse_connections", cfg.ServiceName),
+			Help: "The number of open product change server sent events connections",
+		}),
+		ActiveWsConnections: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("%s_active_ws_connections", cfg.ServiceName),
+			Help: "The number of open product change websocket connections",
+		}),
+		RejectedStreamConnections: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_rejected_stream_connections_total", cfg.ServiceName),
+			Help: "The total number of product change connections rejected over the connection limit",
+		}),
+		SlowStreamConsumers: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_slow_stream_consumers_total", cfg.ServiceName),
+			Help: "The total number of product change connections closed because the client fell behind",
+		}),
+		ProjectionWaitsConfirmed: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_projection_waits_confirmed_total", cfg.ServiceName),
+			Help: "The total number of read your writes commands confirmed by the reader projection",
+		}),
+		ProjectionWaitsPending: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_projection_waits_pending_total", cfg.ServiceName),
+			Help: "The total number of read your writes commands answered with 202 before the projection confirmed them",
+		}),
+		GetCommandStatusHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_command_status_http_requests_total", cfg.ServiceName),
+			Help: "The total number of get command status http requests",
+		}),
+		CommandResultKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_command_result_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of consumed command results",
+		}),
+		ThrottledHttpRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_throttled_http_requests_total", cfg.ServiceName),
+			Help: "The total number of http requests rejected by the rate limiter",
+		}),
+		RateLimiterErrors: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_rate_limiter_errors_total", cfg.ServiceName),
+			Help: "The total number of http requests allowed because the rate limiter failed",
+		}),
+		IdempotentReplays: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_idempotent_replays_total", cfg.ServiceName),
+			Help: "The total number of write requests answered with the stored response of their idempotency key",
+		}),
+		IdempotencyKeyMismatches: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_idempotency_key_mismatches_total", cfg.ServiceName),
+			Help: "The total number of idempotency keys reused with a different request",
+		}),
 	}
 }
@@ -1,25 +1,73 @@
 package middlewares
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/auth"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/idempotency"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/metrics"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/ratelimit"
+	"github.com/herhu/Microservices-PR/pkg/constants"
+	httpErrors "github.com/herhu/Microservices-PR/pkg/http_errors"
+	"github.com/herhu/Microservices-PR/pkg/identity"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	bearerPrefix = "bearer "
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders response headers stored with idempotent responses
+var replayedHeaders = []string{echo.HeaderContentType, echo.HeaderLocation, constants.CommandIDHeader}
+
 type MiddlewareManager interface {
 	RequestLoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc
+	AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc
+	RequireRoles(roles ...string) echo.MiddlewareFunc
+	RateLimitMiddleware(next echo.HandlerFunc) echo.HandlerFunc
+	IdempotencyMiddleware(next echo.HandlerFunc) echo.HandlerFunc
 }
 
 type middlewareManager struct {
-	log logger.Logger
-	cfg *config.Config
+	log            logger.Logger
+	cfg            *config.Config
+	tokenValidator auth.TokenValidator
+	rateLimiter    ratelimit.Limiter
+	idempotency    idempotency.Store
+	metrics        *metrics.ApiGatewayMetrics
 }
 
-func NewMiddlewareManager(log logger.Logger, cfg *config.Config) *middlewareManager {
-	return &middlewareManager{log: log, cfg: cfg}
+// NewMiddlewareManager tokenValidator, rateLimiter and idempotencyStore are only used when their feature is enabled
+func NewMiddlewareManager(
+	log logger.Logger,
+	cfg *config.Config,
+	tokenValidator auth.TokenValidator,
+	rateLimiter ratelimit.Limiter,
+	idempotencyStore idempotency.Store,
+	metrics *metrics.ApiGatewayMetrics,
+) *middlewareManager {
+	return &middlewareManager{
+		log:            log,
+		cfg:            cfg,
+		tokenValidator: tokenValidator,
+		rateLimiter:    rateLimiter,
+		idempotency:    idempotencyStore,
+		metrics:        metrics,
+	}
 }
 
 func (mw *middlewareManager) RequestLoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
@@ -50,3 +98,222 @@ func (mw *middlewareManager) checkIgnoredURI(requestURI string, uriList []string
 	}
 	return false
 }
+
+// AuthMiddleware stores the identity of a valid bearer token in the request context, invalid tokens are rejected.
+// Requests without a token continue anonymous, routes that need a caller require it with RequireRoles.
+func (mw *middlewareManager) AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if !mw.cfg.Auth.Enable {
+			return next(ctx)
+		}
+
+		authorization := ctx.Request().Header.Get(echo.HeaderAuthorization)
+		if authorization == "" {
+			return next(ctx)
+		}
+		if len(authorization) <= len(bearerPrefix) || !strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+			return mw.unauthorized(ctx, auth.ErrInvalidToken)
+		}
+
+		caller, err := mw.tokenValidator.Validate(strings.TrimSpace(authorization[len(bearerPrefix):]))
+		if err != nil {
+			mw.log.WarnMsg("tokenValidator.Validate", err)
+			return mw.unauthorized(ctx, err)
+		}
+
+		ctx.SetRequest(ctx.Request().WithContext(identity.NewContext(ctx.Request().Context(), caller)))
+		return next(ctx)
+	}
+}
+
+// RequireRoles rejects anonymous callers with 401 and callers missing any of the roles with 403
+func (mw *middlewareManager) RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if !mw.cfg.Auth.Enable {
+				return next(ctx)
+			}
+
+			caller := identity.FromContext(ctx.Request().Context())
+			if caller == nil {
+				return mw.unauthorized(ctx, auth.ErrMissingToken)
+			}
+			for _, role := range roles {
+				if !caller.HasRole(role) {
+					return httpErrors.NewForbiddenError(ctx, fmt.Sprintf("missing role %s", role), mw.cfg.Http.DebugErrorsResponse)
+				}
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func (mw *middlewareManager) unauthorized(ctx echo.Context, err error) error {
+	ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+	return httpErrors.NewUnauthorizedError(ctx, err.Error(), mw.cfg.Http.DebugErrorsResponse)
+}
+
+// RateLimitMiddleware limits the requests of a client per route and sets the RateLimit headers,
+// registered after AuthMiddleware so callers are limited by token subject. Limiter errors allow the request.
+func (mw *middlewareManager) RateLimitMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if !mw.cfg.RateLimit.Enable {
+			return next(ctx)
+		}
+
+		result, err := mw.rateLimiter.Allow(ctx.Request().Context(), ctx.Request().Method, ctx.Path(), mw.rateLimitClient(ctx))
+		if err != nil {
+			mw.log.WarnMsg("rateLimiter.Allow", err)
+			mw.metrics.RateLimiterErrors.Inc()
+			return next(ctx)
+		}
+		if result == nil {
+			return next(ctx)
+		}
+
+		header := ctx.Response().Header()
+		header.Set(constants.RateLimitLimitHeader, strconv.Itoa(result.Limit))
+		header.Set(constants.RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		header.Set(constants.RateLimitResetHeader, strconv.Itoa(ceilSeconds(result.Reset)))
+		header.Set(constants.RateLimitPolicyHeader, fmt.Sprintf("%d;w=%d", result.Limit, ceilSeconds(result.Window)))
+
+		if !result.Allowed {
+			mw.metrics.ThrottledHttpRequests.Inc()
+			header.Set(constants.RetryAfterHeader, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return httpErrors.NewTooManyRequestsError(ctx, "rate limit exceeded", mw.cfg.Http.DebugErrorsResponse)
+		}
+
+		return next(ctx)
+	}
+}
+
+// rateLimitClient identifies the client by a known api key, the token subject or the ip
+func (mw *middlewareManager) rateLimitClient(ctx echo.Context) string {
+	if apiKey := ctx.Request().Header.Get(mw.cfg.RateLimit.ApiKeyHeader); apiKey != "" {
+		for _, knownKey := range mw.cfg.RateLimit.ApiKeys {
+			if apiKey == knownKey {
+				sum := sha256.Sum256([]byte(apiKey))
+				return "key:" + hex.EncodeToString(sum[:16])
+			}
+		}
+	}
+	if caller := identity.FromContext(ctx.Request().Context()); caller != nil {
+		return "sub:" + caller.Subject
+	}
+	return "ip:" + ctx.RealIP()
+}
+
+// IdempotencyMiddleware replays the stored response of a request repeated with the same Idempotency-Key,
+// keys are scoped to the caller. A key reused with a different request gets 422 and a key of a request
+// still in progress gets 409. Server errors are not stored so the request can be retried.
+func (mw *middlewareManager) IdempotencyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		idempotencyKey := ctx.Request().Header.Get(constants.IdempotencyKeyHeader)
+		if !mw.cfg.Idempotency.Enable || idempotencyKey == "" {
+			return next(ctx)
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			return httpErrors.NewBadRequestError(ctx, fmt.Sprintf("%s is longer than %d", constants.IdempotencyKeyHeader, maxIdempotencyKeyLength), mw.cfg.Http.DebugErrorsResponse)
+		}
+
+		body, err := io.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return httpErrors.NewBadRequestError(ctx, err.Error(), mw.cfg.Http.DebugErrorsResponse)
+		}
+		ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		// the response is stored even when the client gave up waiting for it
+		storeCtx := context.WithoutCancel(ctx.Request().Context())
+		key := fmt.Sprintf("%s:%s", mw.idempotencyScope(ctx), idempotencyKey)
+		fingerprint := requestFingerprint(ctx.Request(), body)
+
+		stored, token, err := mw.idempotency.Reserve(storeCtx, key, fingerprint)
+		if err != nil {
+			mw.log.WarnMsg("idempotency.Reserve", err)
+			return next(ctx)
+		}
+		if token == "" {
+			return mw.replay(ctx, stored, fingerprint)
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Response().Writer}
+		ctx.Response().Writer = recorder
+
+		err = next(ctx)
+
+		status := ctx.Response().Status
+		if err != nil || !ctx.Response().Committed || status >= http.StatusInternalServerError || status == http.StatusRequestTimeout {
+			if err := mw.idempotency.Release(storeCtx, key, token); err != nil {
+				mw.log.WarnMsg("idempotency.Release", err)
+			}
+			return err
+		}
+
+		response := &idempotency.Response{
+			Fingerprint: fingerprint,
+			StatusCode:  status,
+			Header:      make(map[string]string, len(replayedHeaders)),
+			Body:        recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := ctx.Response().Header().Get(name); value != "" {
+				response.Header[name] = value
+			}
+		}
+		if err := mw.idempotency.Complete(storeCtx, key, token, response); err != nil {
+			mw.log.WarnMsg("idempotency.Complete", err)
+		}
+
+		return nil
+	}
+}
+
+func (mw *middlewareManager) replay(ctx echo.Context, stored *idempotency.Response, fingerprint string) error {
+	if stored.Fingerprint != fingerprint {
+		mw.metrics.IdempotencyKeyMismatches.Inc()
+		return httpErrors.NewUnprocessableEntityError(ctx, "idempotency key reused with a different request", mw.cfg.Http.DebugErrorsResponse)
+	}
+	if !stored.Completed() {
+		return httpErrors.NewConflictError(ctx, "request with the idempotency key is in progress", mw.cfg.Http.DebugErrorsResponse)
+	}
+
+	mw.metrics.IdempotentReplays.Inc()
+	for name, value := range stored.Header {
+		ctx.Response().Header().Set(name, value)
+	}
+	ctx.Response().Header().Set(constants.IdempotentReplayedHeader, "true")
+	ctx.Response().WriteHeader(stored.StatusCode)
+	_, err := ctx.Response().Write(stored.Body)
+	return err
+}
+
+// idempotencyScope keys of different callers never collide, anonymous callers are scoped by ip
+func (mw *middlewareManager) idempotencyScope(ctx echo.Context) string {
+	if caller := identity.FromContext(ctx.Request().Context()); caller != nil {
+		return "sub:" + caller.Subject
+	}
+	return "ip:" + ctx.RealIP()
+}
+
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the written body
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	uuid "github.com/satori/go.uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// publishTracked stores the command as pending and publishes it with the command id header, writer_service reports
+// the result under that id. The command is marked failed when it cannot be published, status store errors only log.
+func publishTracked(
+	ctx context.Context,
+	log logger.Logger,
+	commandStatusRepo repository.Repository,
+	kafkaProducer kafkaClient.Producer,
+	commandID uuid.UUID,
+	envelope *kafkaClient.Envelope,
+	msg kafka.Message,
+) error {
+	status := dto.NewPendingCommandStatus(commandID.String(), envelope.EventType, envelope.AggregateID)
+	if err := commandStatusRepo.PutCommandStatus(ctx, status); err != nil {
+		log.WarnMsg("PutCommandStatus", err)
+	}
+
+	envelope.CommandID = commandID.String()
+	msg.Headers = kafkaClient.WithEnvelope(msg.Headers, envelope)
+
+	if err := kafkaProducer.PublishMessage(ctx, msg); err != nil {
+		status.Status = dto.CommandFailed
+		status.Error = err.Error()
+		status.UpdatedAt = time.Now().UTC()
+		if err := commandStatusRepo.PutCommandStatus(ctx, status); err != nil {
+			log.WarnMsg("PutCommandStatus", err)
+		}
+		return err
+	}
+
+	return nil
+}
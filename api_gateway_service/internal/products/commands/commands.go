@@ -15,26 +15,30 @@ func NewProductCommands(createProduct CreateProductCmdHandler, updateProduct Upd
 	return &ProductCommands{CreateProduct: createProduct, UpdateProduct: updateProduct, DeleteProduct: deleteProduct}
 }
 
+// CreateProductCommand CommandID identifies the command status reported by GET /commands/{id}
 type CreateProductCommand struct {
+	CommandID uuid.UUID
 	CreateDto *dto.CreateProductDto
 }
 
-func NewCreateProductCommand(createDto *dto.CreateProductDto) *CreateProductCommand {
-	return &CreateProductCommand{CreateDto: createDto}
+func NewCreateProductCommand(commandID uuid.UUID, createDto *dto.CreateProductDto) *CreateProductCommand {
+	return &CreateProductCommand{CommandID: commandID, CreateDto: createDto}
 }
 
 type UpdateProductCommand struct {
+	CommandID uuid.UUID
 	UpdateDto *dto.UpdateProductDto
 }
 
-func NewUpdateProductCommand(updateDto *dto.UpdateProductDto) *UpdateProductCommand {
-	return &UpdateProductCommand{UpdateDto: updateDto}
+func NewUpdateProductCommand(commandID uuid.UUID, updateDto *dto.UpdateProductDto) *UpdateProductCommand {
+	return &UpdateProductCommand{CommandID: commandID, UpdateDto: updateDto}
 }
 
 type DeleteProductCommand struct {
+	CommandID uuid.UUID `json:"commandId" validate:"required"`
 	ProductID uuid.UUID `json:"productId" validate:"required"`
 }
 
-func NewDeleteProductCommand(productID uuid.UUID) *DeleteProductCommand {
-	return &DeleteProductCommand{ProductID: productID}
+func NewDeleteProductCommand(commandID uuid.UUID, productID uuid.UUID) *DeleteProductCommand {
+	return &DeleteProductCommand{CommandID: commandID, ProductID: productID}
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
@@ -19,13 +20,14 @@ type CreateProductCmdHandler interface {
 }
 
 type createProductHandler struct {
-	log           logger.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log               logger.Logger
+	cfg               *config.Config
+	kafkaProducer     kafkaClient.Producer
+	commandStatusRepo repository.Repository
 }
 
-func NewCreateProductHandler(log logger.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *createProductHandler {
-	return &createProductHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer}
+func NewCreateProductHandler(log logger.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, commandStatusRepo repository.Repository) *createProductHandler {
+	return &createProductHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer, commandStatusRepo: commandStatusRepo}
 }
 
 func (c *createProductHandler) Handle(ctx context.Context, command *CreateProductCommand) error {
@@ -44,8 +46,11 @@ func (c *createProductHandler) Handle(ctx context.Context, command *CreateProduc
 		return err
 	}
 
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
+	envelope := kafkaClient.NewEnvelope(ctx, kafkaMessages.ProductCreateType, kafkaMessages.ProductCreateSchemaVersion, createDto.GetProductID(), c.cfg.ServiceName)
+
+	return publishTracked(ctx, c.log, c.commandStatusRepo, c.kafkaProducer, command.CommandID, envelope, kafka.Message{
 		Topic:   c.cfg.KafkaTopics.ProductCreate.TopicName,
+		Key:     []byte(createDto.GetProductID()),
 		Value:   dtoBytes,
 		Time:    time.Now().UTC(),
 		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
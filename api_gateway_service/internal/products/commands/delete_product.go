@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
@@ -19,13 +20,14 @@ type DeleteProductCmdHandler interface {
 }
 
 type deleteProductHandler struct {
-	log           logger.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log               logger.Logger
+	cfg               *config.Config
+	kafkaProducer     kafkaClient.Producer
+	commandStatusRepo repository.Repository
 }
 
-func NewDeleteProductHandler(log logger.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *deleteProductHandler {
-	return &deleteProductHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer}
+func NewDeleteProductHandler(log logger.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, commandStatusRepo repository.Repository) *deleteProductHandler {
+	return &deleteProductHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer, commandStatusRepo: commandStatusRepo}
 }
 
 func (c *deleteProductHandler) Handle(ctx context.Context, command *DeleteProductCommand) error {
@@ -39,8 +41,11 @@ func (c *deleteProductHandler) Handle(ctx context.Context, command *DeleteProduc
 		return err
 	}
 
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
+	envelope := kafkaClient.NewEnvelope(ctx, kafkaMessages.ProductDeleteType, kafkaMessages.ProductDeleteSchemaVersion, createDto.GetProductID(), c.cfg.ServiceName)
+
+	return publishTracked(ctx, c.log, c.commandStatusRepo, c.kafkaProducer, command.CommandID, envelope, kafka.Message{
 		Topic:   c.cfg.KafkaTopics.ProductDelete.TopicName,
+		Key:     []byte(createDto.GetProductID()),
 		Value:   dtoBytes,
 		Time:    time.Now().UTC(),
 		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
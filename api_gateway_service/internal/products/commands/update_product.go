@@ -5,11 +5,16 @@ import (
 	"time"
 
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	httpErrors "github.com/herhu/Microservices-PR/pkg/http_errors"
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 )
@@ -19,24 +24,31 @@ type UpdateProductCmdHandler interface {
 }
 
 type updateProductCmdHandler struct {
-	log           logger.Logger
-	cfg           *config.Config
-	kafkaProducer kafkaClient.Producer
+	log               logger.Logger
+	cfg               *config.Config
+	kafkaProducer     kafkaClient.Producer
+	rsClient          readerService.ReaderServiceClient
+	commandStatusRepo repository.Repository
 }
 
-func NewUpdateProductHandler(log logger.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer) *updateProductCmdHandler {
-	return &updateProductCmdHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer}
+func NewUpdateProductHandler(log logger.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, rsClient readerService.ReaderServiceClient, commandStatusRepo repository.Repository) *updateProductCmdHandler {
+	return &updateProductCmdHandler{log: log, cfg: cfg, kafkaProducer: kafkaProducer, rsClient: rsClient, commandStatusRepo: commandStatusRepo}
 }
 
 func (c *updateProductCmdHandler) Handle(ctx context.Context, command *UpdateProductCommand) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "updateProductCmdHandler.Handle")
 	defer span.Finish()
 
+	if err := c.checkVersion(ctx, command.UpdateDto); err != nil {
+		return err
+	}
+
 	updateDto := &kafkaMessages.ProductUpdate{
-		ProductID:   command.UpdateDto.ProductID.String(),
-		Name:        command.UpdateDto.Name,
-		Description: command.UpdateDto.Description,
-		Price:       command.UpdateDto.Price,
+		ProductID:       command.UpdateDto.ProductID.String(),
+		Name:            command.UpdateDto.Name,
+		Description:     command.UpdateDto.Description,
+		Price:           command.UpdateDto.Price,
+		ExpectedVersion: command.UpdateDto.ExpectedVersion,
 	}
 
 	dtoBytes, err := proto.Marshal(updateDto)
@@ -44,10 +56,38 @@ func (c *updateProductCmdHandler) Handle(ctx context.Context, command *UpdatePro
 		return err
 	}
 
-	return c.kafkaProducer.PublishMessage(ctx, kafka.Message{
+	envelope := kafkaClient.NewEnvelope(ctx, kafkaMessages.ProductUpdateType, kafkaMessages.ProductUpdateSchemaVersion, updateDto.GetProductID(), c.cfg.ServiceName)
+
+	return publishTracked(ctx, c.log, c.commandStatusRepo, c.kafkaProducer, command.CommandID, envelope, kafka.Message{
 		Topic:   c.cfg.KafkaTopics.ProductUpdate.TopicName,
+		Key:     []byte(updateDto.GetProductID()),
 		Value:   dtoBytes,
 		Time:    time.Now().UTC(),
 		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
 	})
 }
+
+// checkVersion rejects the update early only when the reader already has a newer version than expected. The reader
+// may lag behind the writer, so an older or missing product is left to the optimistic check of the writer, whose
+// outcome is reported by the command status. ExpectedVersion 0 updates unconditionally.
+func (c *updateProductCmdHandler) checkVersion(ctx context.Context, updateDto *dto.UpdateProductDto) error {
+	if updateDto.ExpectedVersion == 0 {
+		return nil
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "updateProductCmdHandler.checkVersion")
+	defer span.Finish()
+
+	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	res, err := c.rsClient.GetProductById(ctx, &readerService.GetProductByIdReq{ProductID: updateDto.ProductID.String()})
+	if err != nil {
+		c.log.WarnMsg("rsClient.GetProductById", err)
+		return nil
+	}
+
+	if currentVersion := res.GetProduct().GetVersion(); currentVersion > updateDto.ExpectedVersion {
+		return errors.Wrapf(httpErrors.Conflict, "expected version %d, current version %d", updateDto.ExpectedVersion, currentVersion)
+	}
+
+	return nil
+}
@@ -1,9 +1,14 @@
 package v1
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-playground/validator"
+	"github.com/gorilla/websocket"
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/metrics"
@@ -13,14 +18,18 @@ import (
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/products/service"
 	"github.com/herhu/Microservices-PR/pkg/constants"
 	httpErrors "github.com/herhu/Microservices-PR/pkg/http_errors"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	"github.com/herhu/Microservices-PR/pkg/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
+const defaultProjectionWaitTimeout = 5 * time.Second
+
 type productsHandlers struct {
 	group   *echo.Group
 	log     logger.Logger
@@ -29,6 +38,7 @@ type productsHandlers struct {
 	ps      *service.ProductService
 	v       *validator.Validate
 	metrics *metrics.ApiGatewayMetrics
+	streams int64
 }
 
 func NewProductsHandlers(
@@ -46,10 +56,20 @@ func NewProductsHandlers(
 // CreateProduct
 // @Tags Products
 // @Summary Create product
-// @Description Create new product item
+// @Description Create new product item. With read-your-writes consistency the response waits until the reader
+// @Description projection has the product, or returns 202 with a Location to poll when it does not in time.
 // @Accept json
 // @Produce json
+// @Param consistency query string false "read-your-writes to wait for the reader projection, also accepted as X-Consistency header"
 // @Success 201 {object} dto.CreateProductResponseDto
+// @Success 202 {object} dto.CreateProductResponseDto
+// @Header 201,202 {string} X-Command-ID "command id for GET /commands/{id}"
+// @Param Authorization header string true "Bearer token with the catalog:write role"
+// @Param Idempotency-Key header string false "replays the stored response of a repeated request with the same key"
+// @Failure 401 {object} httpErrors.RestError
+// @Failure 403 {object} httpErrors.RestError
+// @Failure 422 {object} httpErrors.RestError
+// @Failure 429 {object} httpErrors.RestError
 // @Router /products [post]
 func (h *productsHandlers) CreateProduct() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -72,14 +92,21 @@ func (h *productsHandlers) CreateProduct() echo.HandlerFunc {
 			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 
-		if err := h.ps.Commands.CreateProduct.Handle(ctx, commands.NewCreateProductCommand(createDto)); err != nil {
+		commandID := h.newCommandID(c)
+		pending, err := h.publishCommand(ctx, c, createDto.ProductID, func(ctx context.Context) error {
+			return h.ps.Commands.CreateProduct.Handle(ctx, commands.NewCreateProductCommand(commandID, createDto))
+		})
+		if err != nil {
 			h.log.WarnMsg("CreateProduct", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 
 		h.metrics.SuccessHttpRequests.Inc()
-		return c.JSON(http.StatusCreated, dto.CreateProductResponseDto{ProductID: createDto.ProductID})
+		if pending {
+			return c.JSON(http.StatusAccepted, dto.CreateProductResponseDto{ProductID: createDto.ProductID, CommandID: commandID})
+		}
+		return c.JSON(http.StatusCreated, dto.CreateProductResponseDto{ProductID: createDto.ProductID, CommandID: commandID})
 	}
 }
 
@@ -119,16 +146,67 @@ func (h *productsHandlers) GetProductByID() echo.HandlerFunc {
 	}
 }
 
+// GetProductsByIDs
+// @Tags Products
+// @Summary Get products by ids
+// @Description Get up to the reader's batch limit of products in one call, missing ids are listed in notFoundIds
+// @Accept json
+// @Produce json
+// @Param request body dto.GetProductsByIdsDto true "Product IDs"
+// @Success 200 {object} dto.ProductsBatchResponse
+// @Failure 400 {object} httpErrors.RestError
+// @Router /products/batch-get [post]
+func (h *productsHandlers) GetProductsByIDs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.GetProductsByIdsHttpRequests.Inc()
+
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "productsHandlers.GetProductsByIDs")
+		defer span.Finish()
+
+		batchDto := &dto.GetProductsByIdsDto{}
+		if err := c.Bind(batchDto); err != nil {
+			h.log.WarnMsg("Bind", err)
+			h.traceErr(span, err)
+			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+
+		if err := h.v.StructCtx(ctx, batchDto); err != nil {
+			h.log.WarnMsg("validate", err)
+			h.traceErr(span, err)
+			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+
+		response, err := h.ps.Queries.GetProductsByIds.Handle(ctx, queries.NewGetProductsByIdsQuery(batchDto.ProductIDs))
+		if err != nil {
+			h.log.WarnMsg("GetProductsByIds", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
+		}
+
+		h.metrics.SuccessHttpRequests.Inc()
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
 // SearchProduct
 // @Tags Products
 // @Summary Search product
-// @Description Get product by name with pagination
+// @Description Search products with pagination, supports terms, "quoted phrases", name:value, description:value, price:10..50, price:>10 and -negation
 // @Accept json
 // @Produce json
-// @Param search query string false "search text"
+// @Param search query string false "search query"
+// @Param orderBy query string false "sort field: name, price, createdAt, updatedAt or relevance, prefix with - for descending"
+// @Param minPrice query number false "minimum price"
+// @Param maxPrice query number false "maximum price"
+// @Param createdFrom query string false "created at or after, RFC3339"
+// @Param createdTo query string false "created at or before, RFC3339"
+// @Param updatedFrom query string false "updated at or after, RFC3339"
+// @Param updatedTo query string false "updated at or before, RFC3339"
+// @Param cursor query string false "cursor mode when present, empty for the first page then nextCursor of the previous page, totals are not returned"
 // @Param page query string false "page number"
 // @Param size query string false "number of elements"
 // @Success 200 {object} dto.ProductsListResponse
+// @Failure 400 {object} httpErrors.RestError
 // @Router /products/search [get]
 func (h *productsHandlers) SearchProduct() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -138,8 +216,26 @@ func (h *productsHandlers) SearchProduct() echo.HandlerFunc {
 		defer span.Finish()
 
 		pq := utils.NewPaginationFromQueryParams(c.QueryParam(constants.Size), c.QueryParam(constants.Page))
+		pq.SetOrderBy(c.QueryParam(constants.OrderBy))
+
+		filter, err := dto.NewSearchProductFilterFromQueryParams(
+			c.QueryParam(constants.MinPrice),
+			c.QueryParam(constants.MaxPrice),
+			c.QueryParam(constants.CreatedFrom),
+			c.QueryParam(constants.CreatedTo),
+			c.QueryParam(constants.UpdatedFrom),
+			c.QueryParam(constants.UpdatedTo),
+		)
+		if err != nil {
+			h.log.WarnMsg("NewSearchProductFilterFromQueryParams", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return c.JSON(http.StatusBadRequest, httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrBadRequest, err.Error()))
+		}
 
-		query := queries.NewSearchProductQuery(c.QueryParam(constants.Search), pq)
+		query := queries.NewSearchProductQuery(c.QueryParam(constants.Search), pq, filter)
+		if cursor, ok := c.QueryParams()[constants.Cursor]; ok {
+			query.WithCursor(cursor[0])
+		}
 		response, err := h.ps.Queries.SearchProduct.Handle(ctx, query)
 		if err != nil {
 			h.log.WarnMsg("SearchProduct", err)
@@ -152,14 +248,190 @@ func (h *productsHandlers) SearchProduct() echo.HandlerFunc {
 	}
 }
 
+// StreamProducts
+// @Tags Products
+// @Summary Stream product changes
+// @Description Server sent events of created, updated and deleted products, the event name is the change type and the event id its resume token.
+// @Description Reconnects resume from Last-Event-ID, an error event carries a RestError and ends the stream, heartbeats are sent as comments.
+// @Produce text/event-stream
+// @Param ids query string false "comma separated product ids"
+// @Param search query string false "search query the changed product must match"
+// @Param resumeToken query string false "resume after this change, defaults to the Last-Event-ID header"
+// @Success 200 {object} dto.ProductChangeResponse
+// @Failure 400 {object} httpErrors.RestError
+// @Failure 503 {object} httpErrors.RestError
+// @Router /products/stream [get]
+func (h *productsHandlers) StreamProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.StreamProductsHttpRequests.Inc()
+
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "productsHandlers.StreamProducts")
+		defer span.Finish()
+
+		query, err := h.watchProductsQuery(c)
+		if err != nil {
+			h.log.WarnMsg("watchProductsQuery", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return c.JSON(http.StatusBadRequest, httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrBadRequest, err.Error()))
+		}
+
+		if !h.acquireStream() {
+			return h.tooManyStreams(c)
+		}
+		defer h.releaseStream()
+
+		h.metrics.ActiveSseConnections.Inc()
+		defer h.metrics.ActiveSseConnections.Dec()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		sub := h.subscribe(ctx, query)
+
+		res := c.Response()
+		rc := http.NewResponseController(res.Writer)
+		res.Header().Set(echo.HeaderContentType, sseContentType)
+		res.Header().Set("Cache-Control", "no-cache")
+		res.Header().Set("X-Accel-Buffering", "no")
+		res.WriteHeader(http.StatusOK)
+		res.Flush()
+
+		heartbeat := time.NewTicker(h.streamHeartbeat())
+		defer heartbeat.Stop()
+
+		for {
+			var err error
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-heartbeat.C:
+				_ = rc.SetWriteDeadline(time.Now().Add(streamWriteWait))
+				_, err = io.WriteString(res, ": heartbeat\n\n")
+			case change, ok := <-sub.changes:
+				_ = rc.SetWriteDeadline(time.Now().Add(streamWriteWait))
+				if !ok {
+					if restErr := h.streamEndErr(sub.err); restErr != nil {
+						_ = writeSseEvent(res, "", streamErrType, restErr)
+						res.Flush()
+					}
+					return nil
+				}
+				err = writeSseEvent(res, change.ResumeToken, change.Type, change)
+			}
+			if err != nil {
+				h.log.WarnMsg("writeSseEvent", err)
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// StreamProductsWs
+// @Tags Products
+// @Summary Stream product changes over websocket
+// @Description Websocket variant of /products/stream, every text message is a ProductChangeResponse.
+// @Description A message of type error carries a RestError before the server closes, the connection is pinged on every heartbeat.
+// @Param ids query string false "comma separated product ids"
+// @Param search query string false "search query the changed product must match"
+// @Param resumeToken query string false "resume after this change"
+// @Success 101 {object} dto.ProductChangeResponse
+// @Failure 400 {object} httpErrors.RestError
+// @Failure 403 "the Origin is neither the gateway nor one of the allowed stream origins"
+// @Failure 503 {object} httpErrors.RestError
+// @Router /products/stream/ws [get]
+func (h *productsHandlers) StreamProductsWs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.metrics.StreamProductsWsRequests.Inc()
+
+		ctx, span := tracing.StartHttpServerTracerSpan(c, "productsHandlers.StreamProductsWs")
+		defer span.Finish()
+
+		query, err := h.watchProductsQuery(c)
+		if err != nil {
+			h.log.WarnMsg("watchProductsQuery", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return c.JSON(http.StatusBadRequest, httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrBadRequest, err.Error()))
+		}
+
+		if !h.acquireStream() {
+			return h.tooManyStreams(c)
+		}
+		defer h.releaseStream()
+
+		conn, err := h.wsUpgrader().Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			h.log.WarnMsg("wsUpgrader.Upgrade", err)
+			h.metrics.ErrorHttpRequests.Inc()
+			return nil
+		}
+		defer conn.Close() // nolint: errcheck
+
+		h.metrics.ActiveWsConnections.Inc()
+		defer h.metrics.ActiveWsConnections.Dec()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			h.wsReadLoop(conn)
+			cancel()
+		}()
+		sub := h.subscribe(ctx, query)
+
+		heartbeat := time.NewTicker(h.streamHeartbeat())
+		defer heartbeat.Stop()
+
+		for {
+			var err error
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-heartbeat.C:
+				err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait))
+			case change, ok := <-sub.changes:
+				_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+				if !ok {
+					if restErr := h.streamEndErr(sub.err); restErr != nil {
+						_ = conn.WriteJSON(map[string]interface{}{"type": streamErrType, "error": restErr})
+						wsWriteClose(conn, websocket.ClosePolicyViolation, http.StatusText(restErr.Status()))
+						return nil
+					}
+					wsWriteClose(conn, websocket.CloseTryAgainLater, "reconnect with the last resume token")
+					return nil
+				}
+				err = conn.WriteJSON(change)
+			}
+			if err != nil {
+				h.log.WarnMsg("conn.Write", err)
+				return nil
+			}
+		}
+	}
+}
+
 // UpdateProduct
 // @Tags Products
 // @Summary Update product
-// @Description Update existing product
+// @Description Update existing product. With read-your-writes consistency the response waits until the reader
+// @Description projection has the update, or returns 202 with a Location to poll when it does not in time.
+// @Description expectedVersion 0 or missing updates unconditionally. Otherwise the writer checks the version after
+// @Description the response, so unless read-your-writes confirmed the update the response is 202 with a Location
+// @Description of the command status, which is rejected when the version did not match.
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param consistency query string false "read-your-writes to wait for the reader projection, also accepted as X-Consistency header"
 // @Success 200 {object} dto.UpdateProductDto
+// @Success 202 {object} dto.UpdateProductDto
+// @Header 200,202 {string} X-Command-ID "command id for GET /commands/{id}"
+// @Failure 409 {object} httpErrors.RestError
+// @Param Authorization header string true "Bearer token with the catalog:write role"
+// @Param Idempotency-Key header string false "replays the stored response of a repeated request with the same key"
+// @Failure 401 {object} httpErrors.RestError
+// @Failure 403 {object} httpErrors.RestError
+// @Failure 422 {object} httpErrors.RestError
+// @Failure 429 {object} httpErrors.RestError
 // @Router /products/{id} [put]
 func (h *productsHandlers) UpdateProduct() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -188,13 +460,25 @@ func (h *productsHandlers) UpdateProduct() echo.HandlerFunc {
 			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 
-		if err := h.ps.Commands.UpdateProduct.Handle(ctx, commands.NewUpdateProductCommand(updateDto)); err != nil {
+		commandID := h.newCommandID(c)
+		pending, err := h.publishCommand(ctx, c, updateDto.ProductID, func(ctx context.Context) error {
+			return h.ps.Commands.UpdateProduct.Handle(ctx, commands.NewUpdateProductCommand(commandID, updateDto))
+		})
+		if err != nil {
 			h.log.WarnMsg("UpdateProduct", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 
 		h.metrics.SuccessHttpRequests.Inc()
+		if pending {
+			return c.JSON(http.StatusAccepted, updateDto)
+		}
+		if updateDto.ExpectedVersion != 0 && !h.readYourWrites(c) {
+			// the version check of the writer decides the outcome, the client polls the command status for it
+			c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/%s", h.cfg.Http.CommandsPath, commandID.String()))
+			return c.JSON(http.StatusAccepted, updateDto)
+		}
 		return c.JSON(http.StatusOK, updateDto)
 	}
 }
@@ -206,7 +490,14 @@ func (h *productsHandlers) UpdateProduct() echo.HandlerFunc {
 // @Accept json
 // @Produce json
 // @Success 200 ""
+// @Header 200 {string} X-Command-ID "command id for GET /commands/{id}"
 // @Param id path string true "Product ID"
+// @Param Authorization header string true "Bearer token with the catalog:write role"
+// @Param Idempotency-Key header string false "replays the stored response of a repeated request with the same key"
+// @Failure 401 {object} httpErrors.RestError
+// @Failure 403 {object} httpErrors.RestError
+// @Failure 422 {object} httpErrors.RestError
+// @Failure 429 {object} httpErrors.RestError
 // @Router /products/{id} [delete]
 func (h *productsHandlers) DeleteProduct() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -222,7 +513,8 @@ func (h *productsHandlers) DeleteProduct() echo.HandlerFunc {
 			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
 		}
 
-		if err := h.ps.Commands.DeleteProduct.Handle(ctx, commands.NewDeleteProductCommand(productUUID)); err != nil {
+		commandID := h.newCommandID(c)
+		if err := h.ps.Commands.DeleteProduct.Handle(ctx, commands.NewDeleteProductCommand(commandID, productUUID)); err != nil {
 			h.log.WarnMsg("DeleteProduct", err)
 			h.metrics.ErrorHttpRequests.Inc()
 			return httpErrors.ErrorCtxResponse(c, err, h.cfg.Http.DebugErrorsResponse)
@@ -233,6 +525,46 @@ func (h *productsHandlers) DeleteProduct() echo.HandlerFunc {
 	}
 }
 
+// publishCommand runs publish, with read-your-writes consistency it then waits for the reader projection.
+// pending is true when the projection did not confirm the change in time, Location then points at the product.
+func (h *productsHandlers) publishCommand(ctx context.Context, c echo.Context, productID uuid.UUID, publish func(ctx context.Context) error) (bool, error) {
+	if !h.readYourWrites(c) {
+		return false, publish(ctx)
+	}
+
+	query := queries.NewAwaitProjectionQuery(productID, kafkaClient.CorrelationIDFromContext(ctx), h.projectionWaitTimeout())
+	if _, err := h.ps.Queries.AwaitProjection.Handle(ctx, query, publish); err != nil {
+		if !errors.Is(err, queries.ErrProjectionPending) {
+			return false, err
+		}
+		h.metrics.ProjectionWaitsPending.Inc()
+		c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/%s", h.cfg.Http.ProductsPath, productID.String()))
+		return true, nil
+	}
+
+	h.metrics.ProjectionWaitsConfirmed.Inc()
+	return false, nil
+}
+
+// newCommandID returns the id the command status is tracked under and sets it on the response
+func (h *productsHandlers) newCommandID(c echo.Context) uuid.UUID {
+	commandID := uuid.NewV4()
+	c.Response().Header().Set(constants.CommandIDHeader, commandID.String())
+	return commandID
+}
+
+func (h *productsHandlers) readYourWrites(c echo.Context) bool {
+	return c.QueryParam(constants.Consistency) == constants.ReadYourWrites ||
+		c.Request().Header.Get(constants.ConsistencyHeader) == constants.ReadYourWrites
+}
+
+func (h *productsHandlers) projectionWaitTimeout() time.Duration {
+	if h.cfg.Http.ProjectionWaitTimeout > 0 {
+		return h.cfg.Http.ProjectionWaitTimeout
+	}
+	return defaultProjectionWaitTimeout
+}
+
 func (h *productsHandlers) traceErr(span opentracing.Span, err error) {
 	span.SetTag("error", true)
 	span.LogKV("error_code", err.Error())
@@ -1,16 +1,20 @@
 package v1
 
 import (
+	"github.com/herhu/Microservices-PR/pkg/constants"
 	"github.com/labstack/echo/v4"
 	"net/http"
 )
 
 func (h *productsHandlers) MapRoutes() {
-	h.group.POST("", h.CreateProduct())
+	h.group.POST("", h.CreateProduct(), h.mw.RequireRoles(constants.CatalogWriteRole), h.mw.IdempotencyMiddleware)
 	h.group.GET("/:id", h.GetProductByID())
 	h.group.GET("/search", h.SearchProduct())
-	h.group.PUT("/:id", h.UpdateProduct())
-	h.group.DELETE("/:id", h.DeleteProduct())
+	h.group.POST("/batch-get", h.GetProductsByIDs())
+	h.group.GET("/stream", h.StreamProducts())
+	h.group.GET("/stream/ws", h.StreamProductsWs())
+	h.group.PUT("/:id", h.UpdateProduct(), h.mw.RequireRoles(constants.CatalogWriteRole), h.mw.IdempotencyMiddleware)
+	h.group.DELETE("/:id", h.DeleteProduct(), h.mw.RequireRoles(constants.CatalogWriteRole), h.mw.IdempotencyMiddleware)
 	h.group.Any("/health", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, "OK")
 	})
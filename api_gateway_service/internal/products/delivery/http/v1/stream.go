@@ -0,0 +1,187 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/products/queries"
+	"github.com/herhu/Microservices-PR/pkg/constants"
+	httpErrors "github.com/herhu/Microservices-PR/pkg/http_errors"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultStreamHeartbeat      = 15 * time.Second
+	defaultStreamBufferSize     = 64
+	defaultMaxStreamConnections = 1000
+
+	streamWriteWait = 10 * time.Second
+	wsReadLimit     = 512
+
+	sseContentType = "text/event-stream"
+	streamErrType  = "error"
+)
+
+// changeSubscription buffers the changes of one connection. A connection that falls behind the changes the
+// gateway retains ends with queries.ErrSlowConsumer, the client reconnects with its last resume token.
+type changeSubscription struct {
+	changes chan *dto.ProductChangeResponse
+	err     error
+}
+
+func (h *productsHandlers) subscribe(ctx context.Context, query *queries.WatchProductsQuery) *changeSubscription {
+	sub := &changeSubscription{changes: make(chan *dto.ProductChangeResponse, h.streamBufferSize())}
+
+	go func() {
+		defer close(sub.changes)
+		sub.err = h.ps.Queries.WatchProducts.Handle(ctx, query, func(change *dto.ProductChangeResponse) error {
+			select {
+			case sub.changes <- change:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return sub
+}
+
+func (h *productsHandlers) watchProductsQuery(c echo.Context) (*queries.WatchProductsQuery, error) {
+	filter, err := dto.NewProductChangeFilterFromQueryParams(c.QueryParam(constants.IDs), c.QueryParam(constants.Search))
+	if err != nil {
+		return nil, err
+	}
+
+	resumeToken := c.QueryParam(constants.ResumeToken)
+	if resumeToken == "" {
+		resumeToken = c.Request().Header.Get(constants.LastEventID)
+	}
+
+	return queries.NewWatchProductsQuery(filter, resumeToken), nil
+}
+
+// acquireStream reserves one of the stream connections shared by server sent events and websockets
+func (h *productsHandlers) acquireStream() bool {
+	if atomic.AddInt64(&h.streams, 1) > int64(h.maxStreamConnections()) {
+		atomic.AddInt64(&h.streams, -1)
+		h.metrics.RejectedStreamConnections.Inc()
+		return false
+	}
+	return true
+}
+
+func (h *productsHandlers) releaseStream() {
+	atomic.AddInt64(&h.streams, -1)
+}
+
+func (h *productsHandlers) tooManyStreams(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, httpErrors.NewRestErrorWithMessage(
+		http.StatusServiceUnavailable,
+		http.StatusText(http.StatusServiceUnavailable),
+		"too many stream connections",
+	))
+}
+
+// streamEndErr returns the error to report to the client, nil when the client should reconnect
+func (h *productsHandlers) streamEndErr(err error) httpErrors.RestErr {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, queries.ErrSlowConsumer) {
+		h.metrics.SlowStreamConsumers.Inc()
+		h.log.WarnMsg("WatchProducts", err)
+		return nil
+	}
+	h.log.WarnMsg("WatchProducts", err)
+	h.metrics.ErrorHttpRequests.Inc()
+	return httpErrors.ParseErrors(err, h.cfg.Http.DebugErrorsResponse)
+}
+
+func writeSseEvent(w io.Writer, id, event string, data interface{}) error {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, dataBytes)
+	return err
+}
+
+// wsReadLoop discards client messages and answers control frames, it returns when the client goes away
+// or misses pongs for two heartbeats
+func (h *productsHandlers) wsReadLoop(conn *websocket.Conn) {
+	readWait := 2 * h.streamHeartbeat()
+
+	conn.SetReadLimit(wsReadLimit)
+	_ = conn.SetReadDeadline(time.Now().Add(readWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readWait))
+	})
+
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+func wsWriteClose(conn *websocket.Conn, code int, text string) {
+	msg := websocket.FormatCloseMessage(code, text)
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(streamWriteWait))
+}
+
+func (h *productsHandlers) wsUpgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 1024, CheckOrigin: h.checkWsOrigin}
+}
+
+// checkWsOrigin accepts requests without Origin, from the gateway origin and from the configured stream origins,
+// "*" allows any origin
+func (h *productsHandlers) checkWsOrigin(r *http.Request) bool {
+	origin := r.Header.Get(echo.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range h.cfg.Http.StreamAllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func (h *productsHandlers) streamHeartbeat() time.Duration {
+	if h.cfg.Http.StreamHeartbeat > 0 {
+		return h.cfg.Http.StreamHeartbeat
+	}
+	return defaultStreamHeartbeat
+}
+
+func (h *productsHandlers) streamBufferSize() int {
+	if h.cfg.Http.StreamBufferSize > 0 {
+		return h.cfg.Http.StreamBufferSize
+	}
+	return defaultStreamBufferSize
+}
+
+func (h *productsHandlers) maxStreamConnections() int {
+	if h.cfg.Http.MaxStreamConnections > 0 {
+		return h.cfg.Http.MaxStreamConnections
+	}
+	return defaultMaxStreamConnections
+}
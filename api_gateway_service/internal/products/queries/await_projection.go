@@ -0,0 +1,86 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	"github.com/herhu/Microservices-PR/pkg/constants"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/tracing"
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// ErrProjectionPending the command was published but the reader projection did not confirm it in time
+var ErrProjectionPending = errors.New("projection pending")
+
+type AwaitProjectionHandler interface {
+	Handle(ctx context.Context, query *AwaitProjectionQuery, publish func(ctx context.Context) error) (*dto.ProductChangeResponse, error)
+}
+
+type awaitProjectionHandler struct {
+	log      logger.Logger
+	cfg      *config.Config
+	rsClient readerService.ReaderServiceClient
+}
+
+func NewAwaitProjectionHandler(log logger.Logger, cfg *config.Config, rsClient readerService.ReaderServiceClient) *awaitProjectionHandler {
+	return &awaitProjectionHandler{log: log, cfg: cfg, rsClient: rsClient}
+}
+
+// Handle watches the product before publish runs so the change cannot be missed, then waits for the change caused
+// by the command. Returns ErrProjectionPending on timeout, and when the watch fails the command is still published.
+func (a *awaitProjectionHandler) Handle(ctx context.Context, query *AwaitProjectionQuery, publish func(ctx context.Context) error) (*dto.ProductChangeResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "awaitProjectionHandler.Handle")
+	defer span.Finish()
+
+	watchCtx, cancel := context.WithTimeout(ctx, query.Timeout)
+	defer cancel()
+
+	stream, err := a.watch(watchCtx, span, query)
+	if err != nil {
+		a.log.WarnMsg("awaitProjectionHandler.watch", err)
+		if err := publish(ctx); err != nil {
+			return nil, err
+		}
+		return nil, ErrProjectionPending
+	}
+
+	if err := publish(ctx); err != nil {
+		return nil, err
+	}
+
+	for {
+		change, err := stream.Recv()
+		if err != nil {
+			if watchCtx.Err() == nil {
+				a.log.WarnMsg("stream.Recv", err)
+			}
+			return nil, ErrProjectionPending
+		}
+		if query.Matches(change) {
+			return dto.ProductChangeResponseFromGrpc(change), nil
+		}
+	}
+}
+
+// watch returns once the reader fixed the watch position, changes applied after that are delivered
+func (a *awaitProjectionHandler) watch(ctx context.Context, span opentracing.Span, query *AwaitProjectionQuery) (readerService.ReaderService_WatchProductsClient, error) {
+	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	stream, err := a.rsClient.WatchProducts(ctx, &readerService.WatchProductsReq{ProductIDs: []string{query.ProductID.String()}})
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := stream.Header()
+	if err != nil {
+		return nil, err
+	}
+	if len(header.Get(constants.ResumeTokenMetadata)) == 0 {
+		return nil, errors.New("watch start was not confirmed")
+	}
+
+	return stream, nil
+}
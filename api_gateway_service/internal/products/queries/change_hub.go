@@ -0,0 +1,213 @@
+package queries
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	"github.com/herhu/Microservices-PR/pkg/constants"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultStreamHistorySize = 1000
+	changeHubRetryDelay      = time.Second
+)
+
+// ErrSlowConsumer the watcher fell behind the changes the gateway retains, it resumes with its last resume token
+var ErrSlowConsumer = errors.New("stream consumer fell behind")
+
+type ChangeHub interface {
+	Run(ctx context.Context)
+	Position(resumeToken string) (int64, bool)
+	Watch(ctx context.Context, position int64, send func(change *dto.ProductChangeResponse) error) error
+}
+
+// changeHub relays one unfiltered reader change stream to every watcher of the gateway. It retains the latest
+// changes so watchers resume from memory, positions are sequence numbers of the relayed changes.
+type changeHub struct {
+	log      logger.Logger
+	cfg      *config.Config
+	rsClient readerService.ReaderServiceClient
+
+	mu       sync.Mutex
+	base     string // resume token the retained changes follow, empty until the reader stream started
+	first    int64  // position of the oldest retained change
+	changes  []*dto.ProductChangeResponse
+	appended chan struct{}
+}
+
+func NewChangeHub(log logger.Logger, cfg *config.Config, rsClient readerService.ReaderServiceClient) *changeHub {
+	return &changeHub{log: log, cfg: cfg, rsClient: rsClient, appended: make(chan struct{})}
+}
+
+// Run relays the reader change stream until the context is done, a broken stream is resumed after the last
+// relayed change. When the reader no longer has it the retained changes are dropped and every watcher falls behind.
+func (h *changeHub) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := h.relay(ctx); err != nil && ctx.Err() == nil {
+			h.log.WarnMsg("changeHub.relay", err)
+			if status.Code(err) == codes.OutOfRange {
+				h.reset()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(changeHubRetryDelay):
+		}
+	}
+}
+
+func (h *changeHub) relay(ctx context.Context) error {
+	stream, err := h.rsClient.WatchProducts(ctx, &readerService.WatchProductsReq{ResumeToken: h.lastToken()})
+	if err != nil {
+		return err
+	}
+
+	header, err := stream.Header()
+	if err != nil {
+		return err
+	}
+	started := header.Get(constants.ResumeTokenMetadata)
+	if len(started) == 0 {
+		// the reader refused the watch before it started, Recv returns the status
+		_, err := stream.Recv()
+		return err
+	}
+	h.start(started[0])
+
+	for {
+		change, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		h.append(dto.ProductChangeResponseFromGrpc(change))
+	}
+}
+
+// Position returns the position after the change of the resume token, an empty token is the newest position.
+// ok is false before the reader stream started and for changes the hub does not retain.
+func (h *changeHub) Position(resumeToken string) (int64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.base == "" {
+		return 0, false
+	}
+	switch resumeToken {
+	case "":
+		return h.first + int64(len(h.changes)), true
+	case h.base:
+		return h.first, true
+	}
+	for i := len(h.changes) - 1; i >= 0; i-- {
+		if h.changes[i].ResumeToken == resumeToken {
+			return h.first + int64(i) + 1, true
+		}
+	}
+	return 0, false
+}
+
+// Watch sends the changes from the position until the context is done or send fails,
+// returns ErrSlowConsumer when the position is no longer retained
+func (h *changeHub) Watch(ctx context.Context, position int64, send func(change *dto.ProductChangeResponse) error) error {
+	for {
+		changes, appended, err := h.read(position)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if err := send(change); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			position++
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-appended:
+		}
+	}
+}
+
+// read returns the retained changes from the position and a channel closed on the next append
+func (h *changeHub) read(position int64) ([]*dto.ProductChangeResponse, <-chan struct{}, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if position < h.first {
+		return nil, nil, ErrSlowConsumer
+	}
+	return h.changes[position-h.first:], h.appended, nil
+}
+
+func (h *changeHub) lastToken() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.changes) > 0 {
+		return h.changes[len(h.changes)-1].ResumeToken
+	}
+	return h.base
+}
+
+// start sets the resume token of the first reader stream, resumed streams start after the last relayed change
+func (h *changeHub) start(resumeToken string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.base == "" {
+		h.base = resumeToken
+	}
+}
+
+func (h *changeHub) append(change *dto.ProductChangeResponse) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.changes = append(h.changes, change)
+	if len(h.changes) > h.historySize() {
+		h.base = h.changes[0].ResumeToken
+		h.changes = h.changes[1:]
+		h.first++
+	}
+	h.notify()
+}
+
+// reset drops the retained changes and moves the oldest position past every watcher
+func (h *changeHub) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.first += int64(len(h.changes)) + 1
+	h.changes = nil
+	h.base = ""
+	h.notify()
+}
+
+func (h *changeHub) notify() {
+	close(h.appended)
+	h.appended = make(chan struct{})
+}
+
+func (h *changeHub) historySize() int {
+	if h.cfg.Http.StreamHistorySize > 0 {
+		return h.cfg.Http.StreamHistorySize
+	}
+	return defaultStreamHistorySize
+}
@@ -0,0 +1,44 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/tracing"
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"github.com/opentracing/opentracing-go"
+)
+
+type GetProductsByIdsHandler interface {
+	Handle(ctx context.Context, query *GetProductsByIdsQuery) (*dto.ProductsBatchResponse, error)
+}
+
+type getProductsByIdsHandler struct {
+	log      logger.Logger
+	cfg      *config.Config
+	rsClient readerService.ReaderServiceClient
+}
+
+func NewGetProductsByIdsHandler(log logger.Logger, cfg *config.Config, rsClient readerService.ReaderServiceClient) *getProductsByIdsHandler {
+	return &getProductsByIdsHandler{log: log, cfg: cfg, rsClient: rsClient}
+}
+
+func (q *getProductsByIdsHandler) Handle(ctx context.Context, query *GetProductsByIdsQuery) (*dto.ProductsBatchResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "getProductsByIdsHandler.Handle")
+	defer span.Finish()
+
+	productIDs := make([]string, 0, len(query.ProductIDs))
+	for _, productID := range query.ProductIDs {
+		productIDs = append(productIDs, productID.String())
+	}
+
+	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	res, err := q.rsClient.GetProductsByIds(ctx, &readerService.GetProductsByIdsReq{ProductIDs: productIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ProductsBatchResponseFromGrpc(res), nil
+}
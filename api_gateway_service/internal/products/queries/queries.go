@@ -1,17 +1,36 @@
 package queries
 
 import (
+	"time"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
 	"github.com/herhu/Microservices-PR/pkg/utils"
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
 	uuid "github.com/satori/go.uuid"
 )
 
 type ProductQueries struct {
-	GetProductById GetProductByIdHandler
-	SearchProduct  SearchProductHandler
+	GetProductById   GetProductByIdHandler
+	GetProductsByIds GetProductsByIdsHandler
+	SearchProduct    SearchProductHandler
+	WatchProducts    WatchProductsHandler
+	AwaitProjection  AwaitProjectionHandler
 }
 
-func NewProductQueries(getProductById GetProductByIdHandler, searchProduct SearchProductHandler) *ProductQueries {
-	return &ProductQueries{GetProductById: getProductById, SearchProduct: searchProduct}
+func NewProductQueries(
+	getProductById GetProductByIdHandler,
+	getProductsByIds GetProductsByIdsHandler,
+	searchProduct SearchProductHandler,
+	watchProducts WatchProductsHandler,
+	awaitProjection AwaitProjectionHandler,
+) *ProductQueries {
+	return &ProductQueries{
+		GetProductById:   getProductById,
+		GetProductsByIds: getProductsByIds,
+		SearchProduct:    searchProduct,
+		WatchProducts:    watchProducts,
+		AwaitProjection:  awaitProjection,
+	}
 }
 
 type GetProductByIdQuery struct {
@@ -22,11 +41,57 @@ func NewGetProductByIdQuery(productID uuid.UUID) *GetProductByIdQuery {
 	return &GetProductByIdQuery{ProductID: productID}
 }
 
+type GetProductsByIdsQuery struct {
+	ProductIDs []uuid.UUID `json:"productIds"`
+}
+
+func NewGetProductsByIdsQuery(productIDs []uuid.UUID) *GetProductsByIdsQuery {
+	return &GetProductsByIdsQuery{ProductIDs: productIDs}
+}
+
 type SearchProductQuery struct {
-	Text       string            `json:"text"`
-	Pagination *utils.Pagination `json:"pagination"`
+	Text       string                   `json:"text"`
+	Pagination *utils.Pagination        `json:"pagination"`
+	Filter     *dto.SearchProductFilter `json:"filter"`
+	CursorMode bool                     `json:"cursorMode"`
+	Cursor     string                   `json:"cursor"`
+}
+
+func NewSearchProductQuery(text string, pagination *utils.Pagination, filter *dto.SearchProductFilter) *SearchProductQuery {
+	return &SearchProductQuery{Text: text, Pagination: pagination, Filter: filter}
+}
+
+// WithCursor switches the query to cursor mode, an empty cursor requests the first page
+func (q *SearchProductQuery) WithCursor(cursor string) *SearchProductQuery {
+	q.CursorMode = true
+	q.Cursor = cursor
+	return q
+}
+
+type WatchProductsQuery struct {
+	Filter      *dto.ProductChangeFilter `json:"filter"`
+	ResumeToken string                   `json:"resumeToken"`
+}
+
+func NewWatchProductsQuery(filter *dto.ProductChangeFilter, resumeToken string) *WatchProductsQuery {
+	return &WatchProductsQuery{Filter: filter, ResumeToken: resumeToken}
+}
+
+// AwaitProjectionQuery matches the change of ProductID caused by the request with CorrelationID,
+// without a correlation id any change of the product matches
+type AwaitProjectionQuery struct {
+	ProductID     uuid.UUID     `json:"productId"`
+	CorrelationID string        `json:"correlationId"`
+	Timeout       time.Duration `json:"timeout"`
+}
+
+func NewAwaitProjectionQuery(productID uuid.UUID, correlationID string, timeout time.Duration) *AwaitProjectionQuery {
+	return &AwaitProjectionQuery{ProductID: productID, CorrelationID: correlationID, Timeout: timeout}
 }
 
-func NewSearchProductQuery(text string, pagination *utils.Pagination) *SearchProductQuery {
-	return &SearchProductQuery{Text: text, Pagination: pagination}
+func (q *AwaitProjectionQuery) Matches(change *readerService.ProductChange) bool {
+	if change.GetProductID() != q.ProductID.String() {
+		return false
+	}
+	return q.CorrelationID == "" || change.GetCorrelationID() == q.CorrelationID
 }
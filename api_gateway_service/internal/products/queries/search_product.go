@@ -30,11 +30,19 @@ func (s *searchProductHandler) Handle(ctx context.Context, query *SearchProductQ
 	defer span.Finish()
 
 	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
-	res, err := s.rsClient.SearchProduct(ctx, &readerService.SearchReq{
-		Search: query.Text,
-		Page:   int64(query.Pagination.GetPage()),
-		Size:   int64(query.Pagination.GetSize()),
-	})
+	req := &readerService.SearchReq{
+		Search:     query.Text,
+		Page:       int64(query.Pagination.GetPage()),
+		Size:       int64(query.Pagination.GetSize()),
+		OrderBy:    query.Pagination.GetOrderBy(),
+		CursorMode: query.CursorMode,
+		Cursor:     query.Cursor,
+	}
+	if query.Filter != nil {
+		query.Filter.ToGrpc(req)
+	}
+
+	res, err := s.rsClient.SearchProduct(ctx, req)
 	if err != nil {
 		return nil, err
 	}
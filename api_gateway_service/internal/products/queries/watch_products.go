@@ -0,0 +1,91 @@
+package queries
+
+import (
+	"context"
+	"io"
+
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/dto"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/tracing"
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"github.com/opentracing/opentracing-go"
+)
+
+type WatchProductsHandler interface {
+	Handle(ctx context.Context, query *WatchProductsQuery, send func(change *dto.ProductChangeResponse) error) error
+}
+
+type watchProductsHandler struct {
+	log      logger.Logger
+	cfg      *config.Config
+	rsClient readerService.ReaderServiceClient
+	hub      ChangeHub
+}
+
+func NewWatchProductsHandler(log logger.Logger, cfg *config.Config, rsClient readerService.ReaderServiceClient, hub ChangeHub) *watchProductsHandler {
+	return &watchProductsHandler{log: log, cfg: cfg, rsClient: rsClient, hub: hub}
+}
+
+// Handle sends the changes matching the filter until the context is done or send fails. Changes come from
+// the gateway change hub, a resume token the hub does not retain is caught up with an own reader stream first.
+func (w *watchProductsHandler) Handle(ctx context.Context, query *WatchProductsQuery, send func(change *dto.ProductChangeResponse) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "watchProductsHandler.Handle")
+	defer span.Finish()
+
+	filtered := func(change *dto.ProductChangeResponse) error {
+		if !query.Filter.Matches(change) {
+			return nil
+		}
+		return send(change)
+	}
+
+	position, ok := w.hub.Position(query.ResumeToken)
+	if !ok {
+		var err error
+		if position, ok, err = w.catchUp(ctx, span, query.ResumeToken, filtered); err != nil || !ok {
+			return err
+		}
+	}
+
+	return w.hub.Watch(ctx, position, filtered)
+}
+
+// catchUp relays an unfiltered reader stream from the resume token until the hub retains the relayed change,
+// then returns the hub position after it. ok is false when the stream ended first.
+func (w *watchProductsHandler) catchUp(
+	ctx context.Context,
+	span opentracing.Span,
+	resumeToken string,
+	send func(change *dto.ProductChangeResponse) error,
+) (int64, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ctx = tracing.InjectTextMapCarrierToGrpcMetaData(ctx, span.Context())
+	stream, err := w.rsClient.WatchProducts(ctx, &readerService.WatchProductsReq{ResumeToken: resumeToken})
+	if err != nil {
+		return 0, false, err
+	}
+
+	for {
+		change, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return 0, false, nil
+			}
+			return 0, false, err
+		}
+
+		res := dto.ProductChangeResponseFromGrpc(change)
+		if err := send(res); err != nil {
+			if ctx.Err() != nil {
+				return 0, false, nil
+			}
+			return 0, false, err
+		}
+		if position, ok := w.hub.Position(res.ResumeToken); ok {
+			return position, true, nil
+		}
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	commandsRepository "github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/products/commands"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/products/queries"
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
@@ -14,17 +15,33 @@ type ProductService struct {
 	Queries  *queries.ProductQueries
 }
 
-func NewProductService(log logger.Logger, cfg *config.Config, kafkaProducer kafkaClient.Producer, rsClient readerService.ReaderServiceClient) *ProductService {
+func NewProductService(
+	log logger.Logger,
+	cfg *config.Config,
+	kafkaProducer kafkaClient.Producer,
+	rsClient readerService.ReaderServiceClient,
+	changeHub queries.ChangeHub,
+	commandStatusRepo commandsRepository.Repository,
+) *ProductService {
 
-	createProductHandler := commands.NewCreateProductHandler(log, cfg, kafkaProducer)
-	updateProductHandler := commands.NewUpdateProductHandler(log, cfg, kafkaProducer)
-	deleteProductHandler := commands.NewDeleteProductHandler(log, cfg, kafkaProducer)
+	createProductHandler := commands.NewCreateProductHandler(log, cfg, kafkaProducer, commandStatusRepo)
+	updateProductHandler := commands.NewUpdateProductHandler(log, cfg, kafkaProducer, rsClient, commandStatusRepo)
+	deleteProductHandler := commands.NewDeleteProductHandler(log, cfg, kafkaProducer, commandStatusRepo)
 
 	getProductByIdHandler := queries.NewGetProductByIdHandler(log, cfg, rsClient)
+	getProductsByIdsHandler := queries.NewGetProductsByIdsHandler(log, cfg, rsClient)
 	searchProductHandler := queries.NewSearchProductHandler(log, cfg, rsClient)
+	watchProductsHandler := queries.NewWatchProductsHandler(log, cfg, rsClient, changeHub)
+	awaitProjectionHandler := queries.NewAwaitProjectionHandler(log, cfg, rsClient)
 
 	productCommands := commands.NewProductCommands(createProductHandler, updateProductHandler, deleteProductHandler)
-	productQueries := queries.NewProductQueries(getProductByIdHandler, searchProductHandler)
+	productQueries := queries.NewProductQueries(
+		getProductByIdHandler,
+		getProductsByIdsHandler,
+		searchProductHandler,
+		watchProductsHandler,
+		awaitProjectionHandler,
+	)
 
 	return &ProductService{Commands: productCommands, Queries: productQueries}
 }
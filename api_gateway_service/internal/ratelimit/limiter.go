@@ -0,0 +1,22 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result of a request against its rule, Reset is the time until the bucket is full again
+// and RetryAfter the time until the next request is allowed
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Window     time.Duration
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	// Allow takes a token from the client bucket of the route rule, returns nil for unlimited routes
+	Allow(ctx context.Context, method string, path string, client string) (*Result, error)
+}
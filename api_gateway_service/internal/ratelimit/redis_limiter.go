@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	redisRateLimitPrefixKey = "gateway:ratelimit"
+	defaultRuleName         = "default"
+	defaultWindow           = time.Second
+)
+
+// tokenBucketScript refills the bucket for the time since the last request, takes a token when one is available
+// and expires the bucket once it would be full again. Returns whether the token was taken and the tokens left.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate) + 1)
+return {allowed, tostring(tokens)}
+`)
+
+type redisLimiter struct {
+	log         logger.Logger
+	cfg         *config.Config
+	redisClient redis.UniversalClient
+}
+
+func NewRedisLimiter(log logger.Logger, cfg *config.Config, redisClient redis.UniversalClient) *redisLimiter {
+	return &redisLimiter{log: log, cfg: cfg, redisClient: redisClient}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, method string, path string, client string) (*Result, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisLimiter.Allow")
+	defer span.Finish()
+
+	rule, ruleName := l.rule(method, path)
+	if rule.Limit <= 0 {
+		return nil, nil
+	}
+
+	window := rule.Window
+	if window <= 0 {
+		window = defaultWindow
+	}
+	capacity := float64(rule.Limit)
+	ratePerMs := capacity / float64(window.Milliseconds())
+
+	reply, err := tokenBucketScript.Run(
+		ctx,
+		l.redisClient,
+		[]string{l.key(ruleName, client)},
+		rule.Limit,
+		strconv.FormatFloat(ratePerMs, 'f', -1, 64),
+		time.Now().UnixMilli(),
+	).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "tokenBucketScript.Run")
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, errors.Errorf("unexpected token bucket reply %v", reply)
+	}
+
+	allowed, _ := values[0].(int64)
+	tokensReply, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensReply, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "strconv.ParseFloat")
+	}
+
+	result := &Result{
+		Allowed:   allowed == 1,
+		Limit:     rule.Limit,
+		Window:    window,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(math.Ceil((capacity-tokens)/ratePerMs)) * time.Millisecond,
+	}
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(math.Ceil((1-tokens)/ratePerMs)) * time.Millisecond
+	}
+	return result, nil
+}
+
+// rule returns the route rule matching the method and route path or the default rule
+func (l *redisLimiter) rule(method string, path string) (config.RateLimitRule, string) {
+	for _, rule := range l.cfg.RateLimit.Routes {
+		if strings.EqualFold(rule.Method, method) && rule.Path == path {
+			return rule, fmt.Sprintf("%s %s", method, path)
+		}
+	}
+	return l.cfg.RateLimit.Default, defaultRuleName
+}
+
+func (l *redisLimiter) key(ruleName string, client string) string {
+	prefix := redisRateLimitPrefixKey
+	if l.cfg.RateLimit.RedisPrefixKey != "" {
+		prefix = l.cfg.RateLimit.RedisPrefixKey
+	}
+	return fmt.Sprintf("%s:%s:%s", prefix, ruleName, client)
+}
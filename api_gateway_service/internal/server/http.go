@@ -1,12 +1,15 @@
 package server
 
 import (
+	"net"
 	"strings"
 	"time"
 
 	"github.com/herhu/Microservices-PR/docs"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/pkg/errors"
 
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
@@ -21,6 +24,12 @@ const (
 )
 
 func (s *server) runHttpServer() error {
+	ipExtractor, err := s.ipExtractor()
+	if err != nil {
+		return err
+	}
+	s.echo.IPExtractor = ipExtractor
+
 	s.mapRoutes()
 
 	s.echo.Server.ReadTimeout = readTimeout
@@ -30,6 +39,24 @@ func (s *server) runHttpServer() error {
 	return s.echo.Start(s.cfg.Http.Port)
 }
 
+// ipExtractor the client ip used to rate limit and scope idempotency keys. X-Forwarded-For is only read
+// behind the configured trusted proxies, otherwise the ip of the connection is used.
+func (s *server) ipExtractor() (echo.IPExtractor, error) {
+	if len(s.cfg.Http.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range s.cfg.Http.TrustedProxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "net.ParseCIDR")
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 func (s *server) mapRoutes() {
 	docs.SwaggerInfo.Version = "1.0"
 	docs.SwaggerInfo.Title = "API Gateway"
@@ -45,11 +72,18 @@ func (s *server) mapRoutes() {
 		DisablePrintStack: true,
 		DisableStackAll:   true,
 	}))
-	s.echo.Use(middleware.RequestID())
+	s.echo.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			ctx := kafkaClient.ContextWithCorrelationID(c.Request().Context(), requestID)
+			c.SetRequest(c.Request().WithContext(ctx))
+		},
+	}))
+	s.echo.Use(s.mw.AuthMiddleware)
+	s.echo.Use(s.mw.RateLimitMiddleware)
 	s.echo.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: gzipLevel,
 		Skipper: func(c echo.Context) bool {
-			return strings.Contains(c.Request().URL.Path, "swagger")
+			return strings.Contains(c.Request().URL.Path, "swagger") || strings.HasPrefix(c.Path(), s.cfg.Http.ProductsPath+"/stream")
 		},
 	}))
 	s.echo.Use(middleware.BodyLimit(bodyLimit))
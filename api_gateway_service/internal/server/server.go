@@ -4,33 +4,46 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/go-playground/validator"
+	"github.com/go-redis/redis/v8"
 	"github.com/herhu/Microservices-PR/api_gateway_service/config"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/auth"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/client"
+	commandsHttp "github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/delivery/http/v1"
+	commandsKafka "github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/delivery/kafka"
+	commandsRepository "github.com/herhu/Microservices-PR/api_gateway_service/internal/commands/repository"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/idempotency"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/metrics"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/middlewares"
 	v1 "github.com/herhu/Microservices-PR/api_gateway_service/internal/products/delivery/http/v1"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/products/queries"
 	"github.com/herhu/Microservices-PR/api_gateway_service/internal/products/service"
+	"github.com/herhu/Microservices-PR/api_gateway_service/internal/ratelimit"
 	"github.com/herhu/Microservices-PR/pkg/interceptors"
 	"github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	redisClient "github.com/herhu/Microservices-PR/pkg/redis"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
 	"github.com/labstack/echo/v4"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 )
 
 type server struct {
-	log  logger.Logger
-	cfg  *config.Config
-	v    *validator.Validate
-	mw   middlewares.MiddlewareManager
-	im   interceptors.InterceptorManager
-	echo *echo.Echo
-	ps   *service.ProductService
-	m    *metrics.ApiGatewayMetrics
+	log           logger.Logger
+	cfg           *config.Config
+	v             *validator.Validate
+	mw            middlewares.MiddlewareManager
+	im            interceptors.InterceptorManager
+	echo          *echo.Echo
+	ps            *service.ProductService
+	m             *metrics.ApiGatewayMetrics
+	redisClient   redis.UniversalClient
+	consumerGroup kafka.ConsumerGroup
 }
 
 func NewServer(log logger.Logger, cfg *config.Config) *server {
@@ -41,9 +54,24 @@ func (s *server) Run() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
 
-	s.mw = middlewares.NewMiddlewareManager(s.log, s.cfg)
-	s.im = interceptors.NewInterceptorManager(s.log)
+	var tokenValidator auth.TokenValidator
+	if s.cfg.Auth.Enable {
+		jwtValidator, err := auth.NewJwtValidator(s.cfg)
+		if err != nil {
+			return errors.Wrap(err, "auth.NewJwtValidator")
+		}
+		tokenValidator = jwtValidator
+	}
+
+	s.redisClient = redisClient.NewUniversalRedisClient(s.cfg.Redis)
+	defer s.redisClient.Close() // nolint: errcheck
+	s.log.Infof("Redis connected: %+v", s.redisClient.PoolStats())
+
 	s.m = metrics.NewApiGatewayMetrics(s.cfg)
+	rateLimiter := ratelimit.NewRedisLimiter(s.log, s.cfg, s.redisClient)
+	idempotencyStore := idempotency.NewRedisStore(s.log, s.cfg, s.redisClient)
+	s.mw = middlewares.NewMiddlewareManager(s.log, s.cfg, tokenValidator, rateLimiter, idempotencyStore, s.m)
+	s.im = interceptors.NewInterceptorManager(s.log)
 
 	readerServiceConn, err := client.NewReaderServiceConn(ctx, s.cfg, s.im)
 	if err != nil {
@@ -52,14 +80,38 @@ func (s *server) Run() error {
 	defer readerServiceConn.Close() // nolint: errcheck
 	rsClient := readerService.NewReaderServiceClient(readerServiceConn)
 
-	kafkaProducer := kafka.NewProducer(s.log, s.cfg.Kafka.Brokers)
+	broker, err := kafka.NewBroker(s.log, s.cfg.Kafka)
+	if err != nil {
+		return errors.Wrap(err, "kafka.NewBroker")
+	}
+	defer broker.Close() // nolint: errcheck
+
+	kafkaProducer := broker.NewProducer()
 	defer kafkaProducer.Close() // nolint: errcheck
 
-	s.ps = service.NewProductService(s.log, s.cfg, kafkaProducer, rsClient)
+	commandStatusRepo := commandsRepository.NewRedisRepository(s.log, s.cfg, s.redisClient)
+
+	changeHub := queries.NewChangeHub(s.log, s.cfg, rsClient)
+	go changeHub.Run(ctx)
+
+	s.ps = service.NewProductService(s.log, s.cfg, kafkaProducer, rsClient, changeHub, commandStatusRepo)
 
 	productHandlers := v1.NewProductsHandlers(s.echo.Group(s.cfg.Http.ProductsPath), s.log, s.mw, s.cfg, s.ps, s.v, s.m)
 	productHandlers.MapRoutes()
 
+	commandsHandlers := commandsHttp.NewCommandsHandlers(s.echo.Group(s.cfg.Http.CommandsPath), s.log, s.cfg, commandStatusRepo, s.m)
+	commandsHandlers.MapRoutes()
+
+	commandResultProcessor := commandsKafka.NewCommandResultProcessor(s.log, s.cfg, commandStatusRepo, s.m)
+	s.consumerGroup = kafka.NewConsumerGroup(broker, s.cfg.Kafka, s.log)
+
+	consumersWg := &sync.WaitGroup{}
+	consumersWg.Add(1)
+	go func() {
+		defer consumersWg.Done()
+		s.consumerGroup.ConsumeTopic(ctx, []string{s.cfg.KafkaTopics.CommandResult.TopicName}, commandsKafka.PoolSize, commandResultProcessor.ProcessMessage)
+	}()
+
 	go func() {
 		if err := s.runHttpServer(); err != nil {
 			s.log.Errorf(" s.runHttpServer: %v", err)
@@ -85,5 +137,7 @@ func (s *server) Run() error {
 		s.log.WarnMsg("echo.Server.Shutdown", err)
 	}
 
+	s.log.Info("Waiting for Kafka consumers to drain")
+	consumersWg.Wait()
 	return nil
 }
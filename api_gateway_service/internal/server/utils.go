@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"github.com/heptiolabs/healthcheck"
+	"github.com/herhu/Microservices-PR/pkg/constants"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
@@ -21,6 +22,8 @@ func (s *server) runHealthCheck(ctx context.Context) {
 		return errors.New("Config not loaded")
 	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
 
+	health.AddReadinessCheck(constants.KafkaConsumer, s.consumerGroup.Ready)
+
 	go func() {
 		s.log.Infof("API_Gateway Kubernetes probes listening on port: %s", s.cfg.Probes.Port)
 		if err := http.ListenAndServe(s.cfg.Probes.Port, health); err != nil {
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	gatewayApp "github.com/herhu/Microservices-PR/api_gateway_service/app"
+	gatewayConfig "github.com/herhu/Microservices-PR/api_gateway_service/config"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	readerApp "github.com/herhu/Microservices-PR/reader_service/app"
+	readerConfig "github.com/herhu/Microservices-PR/reader_service/config"
+	writerApp "github.com/herhu/Microservices-PR/writer_service/app"
+	writerConfig "github.com/herhu/Microservices-PR/writer_service/config"
+)
+
+var (
+	gatewayConfigPath = flag.String("gateway-config", "./api_gateway_service/config/config.yaml", "API Gateway microservice config path")
+	writerConfigPath  = flag.String("writer-config", "./writer_service/config/config.yaml", "Writer microservice config path")
+	readerConfigPath  = flag.String("reader-config", "./reader_service/config/config.yaml", "Reader microservice config path")
+)
+
+// Runs the gateway, writer and reader services in one process sharing the in-memory message bus,
+// every service stops on the interrupt signal
+func main() {
+	flag.Parse()
+
+	writerCfg, err := writerConfig.InitConfig(*writerConfigPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	readerCfg, err := readerConfig.InitConfig(*readerConfigPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	gatewayCfg, err := gatewayConfig.InitConfig(*gatewayConfigPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	kafkaClient.AllowMemoryDriver()
+	writerCfg.Kafka.Driver = kafkaClient.DriverMemory
+	readerCfg.Kafka.Driver = kafkaClient.DriverMemory
+	gatewayCfg.Kafka.Driver = kafkaClient.DriverMemory
+
+	writerLogger := logger.NewAppLogger(writerCfg.Logger)
+	writerLogger.InitLogger()
+	writerLogger.WithName("WriterService")
+
+	readerLogger := logger.NewAppLogger(readerCfg.Logger)
+	readerLogger.InitLogger()
+	readerLogger.WithName("ReaderService")
+
+	gatewayLogger := logger.NewAppLogger(gatewayCfg.Logger)
+	gatewayLogger.InitLogger()
+	gatewayLogger.WithName("ApiGateway")
+
+	errCh := make(chan error, 3)
+	go func() { errCh <- writerApp.Run(writerLogger, writerCfg) }()
+	go func() { errCh <- readerApp.Run(readerLogger, readerCfg) }()
+	go func() { errCh <- gatewayApp.Run(gatewayLogger, gatewayCfg) }()
+
+	for i := 0; i < cap(errCh); i++ {
+		if err := <-errCh; err != nil {
+			log.Fatal(err)
+		}
+	}
+}
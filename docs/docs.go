@@ -19,9 +19,47 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/commands/{id}": {
+            "get": {
+                "description": "Status of a product command by the id returned in the X-Command-ID header: pending, succeeded or failed with the error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Commands"
+                ],
+                "summary": "Get command status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Command ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommandStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    }
+                }
+            }
+        },
         "/products": {
             "post": {
-                "description": "Create new product item",
+                "description": "Create new product item. With read-your-writes consistency the response waits until the reader\nprojection has the product, or returns 202 with a Location to poll when it does not in time.",
                 "consumes": [
                     "application/json"
                 ],
@@ -32,11 +70,114 @@ const docTemplate = `{
                     "Products"
                 ],
                 "summary": "Create product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "read-your-writes to wait for the reader projection, also accepted as X-Consistency header",
+                        "name": "consistency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token with the catalog:write role",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the stored response of a repeated request with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/dto.CreateProductResponseDto"
+                        },
+                        "headers": {
+                            "X-Command-ID": {
+                                "type": "string",
+                                "description": "command id for GET /commands/{id}"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateProductResponseDto"
+                        },
+                        "headers": {
+                            "X-Command-ID": {
+                                "type": "string",
+                                "description": "command id for GET /commands/{id}"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/batch-get": {
+            "post": {
+                "description": "Get up to the reader's batch limit of products in one call, missing ids are listed in notFoundIds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get products by ids",
+                "parameters": [
+                    {
+                        "description": "Product IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetProductsByIdsDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductsBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
                         }
                     }
                 }
@@ -44,7 +185,7 @@ const docTemplate = `{
         },
         "/products/search": {
             "get": {
-                "description": "Get product by name with pagination",
+                "description": "Search products with pagination, supports terms, \"quoted phrases\", name:value, description:value, price:10..50, price:\u003e10 and -negation",
                 "consumes": [
                     "application/json"
                 ],
@@ -58,10 +199,58 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "search text",
+                        "description": "search query",
                         "name": "search",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "sort field: name, price, createdAt, updatedAt or relevance, prefix with - for descending",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "minimum price",
+                        "name": "minPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "maximum price",
+                        "name": "maxPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after, RFC3339",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before, RFC3339",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "updated at or after, RFC3339",
+                        "name": "updatedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "updated at or before, RFC3339",
+                        "name": "updatedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor mode when present, empty for the first page then nextCursor of the previous page, totals are not returned",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "page number",
@@ -81,6 +270,116 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ProductsListResponse"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/stream": {
+            "get": {
+                "description": "Server sent events of created, updated and deleted products, the event name is the change type and the event id its resume token.\nReconnects resume from Last-Event-ID, an error event carries a RestError and ends the stream, heartbeats are sent as comments.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Stream product changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "comma separated product ids",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query the changed product must match",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resume after this change, defaults to the Last-Event-ID header",
+                        "name": "resumeToken",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/stream/ws": {
+            "get": {
+                "description": "Websocket variant of /products/stream, every text message is a ProductChangeResponse.\nA message of type error carries a RestError before the server closes, the connection is pinged on every heartbeat.",
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Stream product changes over websocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "comma separated product ids",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query the changed product must match",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resume after this change",
+                        "name": "resumeToken",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "403": {
+                        "description": "the Origin is neither the gateway nor one of the allowed stream origins"
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
                     }
                 }
             }
@@ -117,7 +416,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update existing product",
+                "description": "Update existing product. With read-your-writes consistency the response waits until the reader\nprojection has the update, or returns 202 with a Location to poll when it does not in time.\nexpectedVersion 0 or missing updates unconditionally. Otherwise the writer checks the version after\nthe response, so unless read-your-writes confirmed the update the response is 202 with a Location\nof the command status, which is rejected when the version did not match.",
                 "consumes": [
                     "application/json"
                 ],
@@ -135,6 +434,25 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "read-your-writes to wait for the reader projection, also accepted as X-Consistency header",
+                        "name": "consistency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token with the catalog:write role",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the stored response of a repeated request with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -142,6 +460,54 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/dto.UpdateProductDto"
+                        },
+                        "headers": {
+                            "X-Command-ID": {
+                                "type": "string",
+                                "description": "command id for GET /commands/{id}"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateProductDto"
+                        },
+                        "headers": {
+                            "X-Command-ID": {
+                                "type": "string",
+                                "description": "command id for GET /commands/{id}"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
                         }
                     }
                 }
@@ -165,28 +531,138 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token with the catalog:write role",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the stored response of a repeated request with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": ""
+                        "description": "",
+                        "headers": {
+                            "X-Command-ID": {
+                                "type": "string",
+                                "description": "command id for GET /commands/{id}"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httpErrors.RestError"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "dto.CommandStatusResponse": {
+            "type": "object",
+            "properties": {
+                "commandId": {
+                    "type": "string"
+                },
+                "commandType": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.CreateProductResponseDto": {
             "type": "object",
             "required": [
                 "productId"
             ],
             "properties": {
+                "commandId": {
+                    "type": "string"
+                },
                 "productId": {
                     "type": "string"
                 }
             }
         },
+        "dto.GetProductsByIdsDto": {
+            "type": "object",
+            "required": [
+                "productIds"
+            ],
+            "properties": {
+                "productIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ProductChangeResponse": {
+            "type": "object",
+            "properties": {
+                "occurredAt": {
+                    "type": "string"
+                },
+                "product": {
+                    "$ref": "#/definitions/dto.ProductResponse"
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "resumeToken": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
         "dto.ProductResponse": {
             "type": "object",
             "properties": {
@@ -207,6 +683,26 @@ const docTemplate = `{
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ProductsBatchResponse": {
+            "type": "object",
+            "properties": {
+                "notFoundIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProductResponse"
+                    }
                 }
             }
         },
@@ -216,6 +712,9 @@ const docTemplate = `{
                 "hasMore": {
                     "type": "boolean"
                 },
+                "nextCursor": {
+                    "type": "string"
+                },
                 "page": {
                     "type": "integer"
                 },
@@ -250,6 +749,10 @@ const docTemplate = `{
                     "maxLength": 5000,
                     "minLength": 0
                 },
+                "expectedVersion": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 255,
@@ -265,6 +768,21 @@ const docTemplate = `{
                     "minLength": 0
                 }
             }
+        },
+        "httpErrors.RestError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {},
+                "status": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           BIGSERIAL PRIMARY KEY,
+    aggregate_id UUID         NOT NULL,
+    topic        VARCHAR(250) NOT NULL CHECK ( topic <> '' ),
+    payload      BYTEA        NOT NULL,
+    headers      JSONB        NOT NULL     DEFAULT '[]',
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at      TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
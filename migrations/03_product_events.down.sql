@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_snapshots CASCADE;
+DROP TABLE IF EXISTS product_events CASCADE;
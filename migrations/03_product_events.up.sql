@@ -0,0 +1,37 @@
+DROP TABLE IF EXISTS product_events CASCADE;
+DROP TABLE IF EXISTS product_snapshots CASCADE;
+
+
+CREATE TABLE product_events
+(
+    aggregate_id UUID                     NOT NULL,
+    version      BIGINT                   NOT NULL CHECK ( version > 0 ),
+    event_type   VARCHAR(250)             NOT NULL CHECK ( event_type <> '' ),
+    data         JSONB                    NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (aggregate_id, version)
+);
+
+CREATE TABLE product_snapshots
+(
+    aggregate_id UUID                     NOT NULL,
+    version      BIGINT                   NOT NULL CHECK ( version > 0 ),
+    data         JSONB                    NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (aggregate_id, version)
+);
+
+INSERT INTO product_events (aggregate_id, version, event_type, data, created_at)
+SELECT p.product_id,
+       1,
+       'ProductCreated',
+       jsonb_build_object(
+               'productId', p.product_id,
+               'name', p.name,
+               'description', p.description,
+               'price', p.price,
+               'createdAt', p.created_at,
+               'updatedAt', p.updated_at
+           ),
+       p.created_at
+FROM products p;
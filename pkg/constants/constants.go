@@ -5,19 +5,25 @@ const (
 	HttpPort       = "HTTP_PORT"
 	ConfigPath     = "CONFIG_PATH"
 	KafkaBrokers   = "KAFKA_BROKERS"
+	KafkaDriver    = "KAFKA_DRIVER"
 	JaegerHostPort = "JAEGER_HOST"
 	RedisAddr      = "REDIS_ADDR"
 	MongoDbURI     = "MONGO_URI"
 	PostgresqlHost = "POSTGRES_HOST"
 	PostgresqlPort = "POSTGRES_PORT"
+	JwtEnableHs256 = "JWT_ENABLE_HS256"
+	JwtHmacSecret  = "JWT_HMAC_SECRET"
+	JwtJwksPath    = "JWT_JWKS_PATH"
 
 	ReaderServicePort = "READER_SERVICE"
+	WriterServicePort = "WRITER_SERVICE"
 
-	Yaml     = "yaml"
-	Redis    = "redis"
-	Kafka    = "kafka"
-	Postgres = "postgres"
-	MongoDB  = "mongo"
+	Yaml          = "yaml"
+	Redis         = "redis"
+	Kafka         = "kafka"
+	KafkaConsumer = "kafka_consumer"
+	Postgres      = "postgres"
+	MongoDB       = "mongo"
 
 	GRPC     = "GRPC"
 	SIZE     = "SIZE"
@@ -38,8 +44,41 @@ const (
 	Offset    = "offset"
 	Time      = "time"
 
-	Page   = "page"
-	Size   = "size"
-	Search = "search"
-	ID     = "id"
+	Page        = "page"
+	Size        = "size"
+	Search      = "search"
+	ID          = "id"
+	OrderBy     = "orderBy"
+	MinPrice    = "minPrice"
+	MaxPrice    = "maxPrice"
+	CreatedFrom = "createdFrom"
+	CreatedTo   = "createdTo"
+	UpdatedFrom = "updatedFrom"
+	UpdatedTo   = "updatedTo"
+	Cursor      = "cursor"
+	IDs         = "ids"
+	ResumeToken = "resumeToken"
+	LastEventID = "Last-Event-ID"
+
+	Consistency       = "consistency"
+	ConsistencyHeader = "X-Consistency"
+	ReadYourWrites    = "read-your-writes"
+	CommandIDHeader   = "X-Command-ID"
+
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	CatalogWriteRole = "catalog:write"
+
+	// ResumeTokenMetadata grpc header of WatchProducts, sent once the watch start position is fixed
+	ResumeTokenMetadata = "resume-token"
+	// CallerSubjectMetadata and CallerRolesMetadata identity of the authenticated gateway caller
+	CallerSubjectMetadata = "caller-subject"
+	CallerRolesMetadata   = "caller-roles"
 )
@@ -12,6 +12,8 @@ import (
 
 	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -19,6 +21,10 @@ const (
 	ErrNotFound            = "Not Found"
 	ErrUnauthorized        = "Unauthorized"
 	ErrRequestTimeout      = "Request Timeout"
+	ErrConflict            = "Conflict"
+	ErrGone                = "Gone"
+	ErrTooManyRequests     = "Too Many Requests"
+	ErrUnprocessableEntity = "Unprocessable Entity"
 	ErrInvalidEmail        = "Invalid email"
 	ErrInvalidPassword     = "Invalid password"
 	ErrInvalidField        = "Invalid field"
@@ -31,6 +37,9 @@ var (
 	NotFound            = errors.New("Not Found")
 	Unauthorized        = errors.New("Unauthorized")
 	Forbidden           = errors.New("Forbidden")
+	Conflict            = errors.New("Conflict")
+	TooManyRequests     = errors.New("Too Many Requests")
+	UnprocessableEntity = errors.New("Unprocessable Entity")
 	InternalServerError = errors.New("Internal Server Error")
 )
 
@@ -156,6 +165,48 @@ func NewForbiddenError(ctx echo.Context, causes interface{}, debug bool) error {
 	return ctx.JSON(http.StatusForbidden, restError)
 }
 
+// NewConflictError New Conflict Error
+func NewConflictError(ctx echo.Context, causes interface{}, debug bool) error {
+
+	restError := RestError{
+		ErrStatus: http.StatusConflict,
+		ErrError:  Conflict.Error(),
+		Timestamp: time.Now().UTC(),
+	}
+	if debug {
+		restError.ErrMessage = causes
+	}
+	return ctx.JSON(http.StatusConflict, restError)
+}
+
+// NewUnprocessableEntityError New Unprocessable Entity Error
+func NewUnprocessableEntityError(ctx echo.Context, causes interface{}, debug bool) error {
+
+	restError := RestError{
+		ErrStatus: http.StatusUnprocessableEntity,
+		ErrError:  UnprocessableEntity.Error(),
+		Timestamp: time.Now().UTC(),
+	}
+	if debug {
+		restError.ErrMessage = causes
+	}
+	return ctx.JSON(http.StatusUnprocessableEntity, restError)
+}
+
+// NewTooManyRequestsError New Too Many Requests Error
+func NewTooManyRequestsError(ctx echo.Context, causes interface{}, debug bool) error {
+
+	restError := RestError{
+		ErrStatus: http.StatusTooManyRequests,
+		ErrError:  TooManyRequests.Error(),
+		Timestamp: time.Now().UTC(),
+	}
+	if debug {
+		restError.ErrMessage = causes
+	}
+	return ctx.JSON(http.StatusTooManyRequests, restError)
+}
+
 // NewInternalServerError New Internal Server Error
 func NewInternalServerError(ctx echo.Context, causes interface{}, debug bool) error {
 
@@ -175,12 +226,26 @@ func ParseErrors(err error, debug bool) RestErr {
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return NewRestError(http.StatusNotFound, ErrNotFound, err.Error(), debug)
+	case errors.Is(err, NotFound):
+		return NewRestError(http.StatusNotFound, ErrNotFound, err.Error(), debug)
 	case errors.Is(err, context.DeadlineExceeded):
 		return NewRestError(http.StatusRequestTimeout, ErrRequestTimeout, err.Error(), debug)
 	case errors.Is(err, Unauthorized):
 		return NewRestError(http.StatusUnauthorized, ErrUnauthorized, err.Error(), debug)
 	case errors.Is(err, WrongCredentials):
 		return NewRestError(http.StatusUnauthorized, ErrUnauthorized, err.Error(), debug)
+	case errors.Is(err, Conflict):
+		return NewRestError(http.StatusConflict, ErrConflict, err.Error(), debug)
+	case errors.Is(err, UnprocessableEntity):
+		return NewRestError(http.StatusUnprocessableEntity, ErrUnprocessableEntity, err.Error(), debug)
+	case errors.Is(err, TooManyRequests):
+		return NewRestError(http.StatusTooManyRequests, ErrTooManyRequests, err.Error(), debug)
+	case status.Code(err) == codes.Aborted:
+		return NewRestError(http.StatusConflict, ErrConflict, err.Error(), debug)
+	case status.Code(err) == codes.InvalidArgument:
+		return NewRestErrorWithMessage(http.StatusBadRequest, ErrBadRequest, status.Convert(err).Message())
+	case status.Code(err) == codes.OutOfRange:
+		return NewRestErrorWithMessage(http.StatusGone, ErrGone, status.Convert(err).Message())
 	case strings.Contains(strings.ToLower(err.Error()), "sqlstate"):
 		return parseSqlErrors(err, debug)
 	case strings.Contains(strings.ToLower(err.Error()), "field validation"):
@@ -0,0 +1,65 @@
+package identity
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/pkg/constants"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type identityKey struct{}
+
+// Identity authenticated caller, subject and roles of the validated token
+type Identity struct {
+	Subject string
+	Roles   []string
+}
+
+func (i *Identity) HasRole(role string) bool {
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// NewContext stores caller identity propagated to published messages and grpc calls
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns nil for anonymous callers
+func FromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// UnaryClientInterceptor sends the caller identity as grpc metadata
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor sends the caller identity as grpc metadata
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	identity := FromContext(ctx)
+	if identity == nil {
+		return ctx
+	}
+
+	kv := make([]string, 0, 2+2*len(identity.Roles))
+	kv = append(kv, constants.CallerSubjectMetadata, identity.Subject)
+	for _, role := range identity.Roles {
+		kv = append(kv, constants.CallerRolesMetadata, role)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
@@ -0,0 +1,38 @@
+package kafka
+
+import (
+	"context"
+	"time"
+)
+
+// backoff exponential delay between retries of broker calls, from fetchBackoffMin doubling up to fetchBackoffMax
+type backoff struct {
+	delay time.Duration
+}
+
+func newBackoff() *backoff {
+	return &backoff{delay: fetchBackoffMin}
+}
+
+// Wait sleeps the current delay and doubles it, returns false when ctx is done first
+func (b *backoff) Wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(b.delay):
+	}
+
+	if b.delay *= 2; b.delay > fetchBackoffMax {
+		b.delay = fetchBackoffMax
+	}
+	return true
+}
+
+// Delay returns the delay of the next Wait
+func (b *backoff) Delay() time.Duration {
+	return b.delay
+}
+
+func (b *backoff) Reset() {
+	b.delay = fetchBackoffMin
+}
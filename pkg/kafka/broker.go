@@ -0,0 +1,198 @@
+package kafka
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+// Broker drivers
+const (
+	DriverKafka  = "kafka"
+	DriverMemory = "memory"
+)
+
+var (
+	ErrMemoryDriverNotAllowed = errors.New("kafka memory driver is only supported when all services run in one process")
+
+	memoryDriverAllowed atomic.Bool
+)
+
+// AllowMemoryDriver allows the memory driver, services started as separate processes would not see each other
+// messages so only the single process runner allows it
+func AllowMemoryDriver() {
+	memoryDriverAllowed.Store(true)
+}
+
+// Reader consumer group reader, implemented by *kafka.Reader and the in-memory broker reader
+type Reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Broker creates producers and consumer group readers of one message bus
+type Broker interface {
+	NewProducer() Producer
+	NewReader(groupTopics []string, groupID string) Reader
+	CreateTopics(ctx context.Context, topics ...kafka.TopicConfig) error
+	EndOffsets(ctx context.Context, topics ...string) (map[string]map[int]int64, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// NewBroker creates broker of the configured driver, the memory driver returns one broker shared by the process
+func NewBroker(log logger.Logger, cfg *Config) (Broker, error) {
+	switch cfg.Driver {
+	case "", DriverKafka:
+		return newKafkaBroker(log, cfg), nil
+	case DriverMemory:
+		if !memoryDriverAllowed.Load() {
+			return nil, ErrMemoryDriverNotAllowed
+		}
+		return sharedMemoryBroker, nil
+	default:
+		return nil, errors.Errorf("unknown kafka driver: %s", cfg.Driver)
+	}
+}
+
+type kafkaBroker struct {
+	log  logger.Logger
+	cfg  *Config
+	mu   sync.Mutex
+	conn *kafka.Conn
+}
+
+func newKafkaBroker(log logger.Logger, cfg *Config) *kafkaBroker {
+	return &kafkaBroker{log: log, cfg: cfg}
+}
+
+func (b *kafkaBroker) NewProducer() Producer {
+	return NewProducer(b.log, b.cfg.Brokers)
+}
+
+func (b *kafkaBroker) NewReader(groupTopics []string, groupID string) Reader {
+	return NewKafkaGroupReader(b.cfg.Brokers, groupTopics, groupID)
+}
+
+// CreateTopics creates topics through the cluster controller, existing topics are left as is
+func (b *kafkaBroker) CreateTopics(ctx context.Context, topics ...kafka.TopicConfig) error {
+	conn, err := b.getConn(ctx)
+	if err != nil {
+		return err
+	}
+
+	controller, err := conn.Controller()
+	if err != nil {
+		return errors.Wrap(err, "kafkaConn.Controller")
+	}
+
+	controllerURI := net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port))
+	b.log.Infof("kafka controller uri: %s", controllerURI)
+
+	controllerConn, err := kafka.DialContext(ctx, "tcp", controllerURI)
+	if err != nil {
+		return errors.Wrap(err, "kafka.DialContext")
+	}
+	defer controllerConn.Close() // nolint: errcheck
+
+	if err := controllerConn.CreateTopics(topics...); err != nil {
+		return errors.Wrap(err, "kafkaConn.CreateTopics")
+	}
+
+	return nil
+}
+
+// EndOffsets offsets the next messages published to the topic partitions get, by topic and partition
+func (b *kafkaBroker) EndOffsets(ctx context.Context, topics ...string) (map[string]map[int]int64, error) {
+	client := &kafka.Client{Addr: kafka.TCP(b.cfg.Brokers...), Timeout: listOffsetsTimeout}
+
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: topics})
+	if err != nil {
+		return nil, errors.Wrap(err, "kafkaClient.Metadata")
+	}
+
+	request := &kafka.ListOffsetsRequest{Topics: make(map[string][]kafka.OffsetRequest, len(metadata.Topics))}
+	for _, topic := range metadata.Topics {
+		if topic.Error != nil {
+			return nil, errors.Wrapf(topic.Error, "topic %s metadata", topic.Name)
+		}
+		for _, partition := range topic.Partitions {
+			request.Topics[topic.Name] = append(request.Topics[topic.Name], kafka.LastOffsetOf(partition.ID))
+		}
+	}
+
+	response, err := client.ListOffsets(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafkaClient.ListOffsets")
+	}
+
+	offsets := make(map[string]map[int]int64, len(response.Topics))
+	for topic, partitions := range response.Topics {
+		offsets[topic] = make(map[int]int64, len(partitions))
+		for _, partition := range partitions {
+			if partition.Error != nil {
+				return nil, errors.Wrapf(partition.Error, "topic %s partition %d offsets", topic, partition.Partition)
+			}
+			offsets[topic][partition.Partition] = partition.LastOffset
+		}
+	}
+	return offsets, nil
+}
+
+// Ping checks the connection to the brokers, a broken connection is dialed again on the next call
+func (b *kafkaBroker) Ping(ctx context.Context) error {
+	conn, err := b.getConn(ctx)
+	if err != nil {
+		return err
+	}
+
+	brokers, err := conn.Brokers()
+	if err != nil {
+		b.resetConn()
+		return errors.Wrap(err, "kafkaConn.Brokers")
+	}
+
+	b.log.Debugf("kafka brokers: %+v", brokers)
+	return nil
+}
+
+func (b *kafkaBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn == nil {
+		return nil
+	}
+	err := b.conn.Close()
+	b.conn = nil
+	return err
+}
+
+func (b *kafkaBroker) getConn(ctx context.Context) (*kafka.Conn, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn != nil {
+		return b.conn, nil
+	}
+
+	conn, err := NewKafkaConn(ctx, b.cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka.NewKafkaConn")
+	}
+	b.conn = conn
+	return conn, nil
+}
+
+func (b *kafkaBroker) resetConn() {
+	if err := b.Close(); err != nil {
+		b.log.Warnf("kafkaBroker.conn.Close: %v", err)
+	}
+}
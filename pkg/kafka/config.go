@@ -1,10 +1,20 @@
 package kafka
 
+import "time"
+
 // Config kafka config
 type Config struct {
-	Brokers    []string `mapstructure:"brokers"`
-	GroupID    string   `mapstructure:"groupID"`
-	InitTopics bool     `mapstructure:"initTopics"`
+	Driver     string         `mapstructure:"driver"`
+	Brokers    []string       `mapstructure:"brokers"`
+	GroupID    string         `mapstructure:"groupID"`
+	InitTopics bool           `mapstructure:"initTopics"`
+	Retry      RetryConfig    `mapstructure:"retry"`
+	Consumer   ConsumerConfig `mapstructure:"consumer"`
+}
+
+// ConsumerConfig consumer group lifecycle config
+type ConsumerConfig struct {
+	DrainTimeout time.Duration `mapstructure:"drainTimeout"`
 }
 
 // TopicConfig kafka topic config
@@ -12,6 +12,11 @@ const (
 	maxAttempts            = 3
 	dialTimeout            = 3 * time.Minute
 	maxWait                = 1 * time.Second
+	workerQueueSize        = 100
+	fetchBackoffMin        = 100 * time.Millisecond
+	fetchBackoffMax        = 10 * time.Second
+	defaultDrainTimeout    = 30 * time.Second
+	listOffsetsTimeout     = 10 * time.Second
 
 	writerReadTimeout  = 10 * time.Second
 	writerWriteTimeout = 10 * time.Second
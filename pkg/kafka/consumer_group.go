@@ -2,76 +2,70 @@ package kafka
 
 import (
 	"context"
+	"hash/fnv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
-	"github.com/segmentio/kafka-go/compress"
+)
+
+var (
+	ErrConsumerNotReady = errors.New("kafka consumer group is not consuming")
 )
 
 // MessageProcessor processor methods must implement kafka.Worker func method interface
 type MessageProcessor interface {
-	ProcessMessages(ctx context.Context, r *kafka.Reader, wg *sync.WaitGroup, workerID int)
+	ProcessMessage(ctx context.Context, r Reader, m kafka.Message, workerID int)
 }
 
-// Worker kafka consumer worker fetch and process messages from reader
-type Worker func(ctx context.Context, r *kafka.Reader, wg *sync.WaitGroup, workerID int)
+// Worker kafka consumer worker processes one fetched message and commits it
+type Worker func(ctx context.Context, r Reader, m kafka.Message, workerID int)
 
 type ConsumerGroup interface {
-	ConsumeTopic(ctx context.Context, cancel context.CancelFunc, groupID, topic string, poolSize int, worker Worker)
-	GetNewKafkaReader(kafkaURL []string, topic, groupID string) *kafka.Reader
-	GetNewKafkaWriter(topic string) *kafka.Writer
+	ConsumeTopic(ctx context.Context, groupTopics []string, poolSize int, worker Worker)
+	Ready() error
 }
 
 type consumerGroup struct {
-	Brokers []string
-	GroupID string
-	log     logger.Logger
+	broker       Broker
+	GroupID      string
+	drainTimeout time.Duration
+	log          logger.Logger
+	ready        atomic.Bool
 }
 
 // NewConsumerGroup kafka consumer group constructor
-func NewConsumerGroup(brokers []string, groupID string, log logger.Logger) *consumerGroup {
-	return &consumerGroup{Brokers: brokers, GroupID: groupID, log: log}
-}
-
-// GetNewKafkaReader create new kafka reader
-func (c *consumerGroup) GetNewKafkaReader(kafkaURL []string, groupTopics []string, groupID string) *kafka.Reader {
-	return kafka.NewReader(kafka.ReaderConfig{
-		Brokers:                kafkaURL,
-		GroupID:                groupID,
-		GroupTopics:            groupTopics,
-		MinBytes:               minBytes,
-		MaxBytes:               maxBytes,
-		QueueCapacity:          queueCapacity,
-		HeartbeatInterval:      heartbeatInterval,
-		CommitInterval:         commitInterval,
-		PartitionWatchInterval: partitionWatchInterval,
-		MaxAttempts:            maxAttempts,
-		MaxWait:                maxWait,
-		Dialer: &kafka.Dialer{
-			Timeout: dialTimeout,
-		},
-	})
+func NewConsumerGroup(broker Broker, cfg *Config, log logger.Logger) *consumerGroup {
+	drainTimeout := cfg.Consumer.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+	return &consumerGroup{broker: broker, GroupID: cfg.GroupID, drainTimeout: drainTimeout, log: log}
 }
 
-// GetNewKafkaWriter create new kafka producer
-func (c *consumerGroup) GetNewKafkaWriter() *kafka.Writer {
-	w := &kafka.Writer{
-		Addr:         kafka.TCP(c.Brokers...),
-		Balancer:     &kafka.LeastBytes{},
-		RequiredAcks: writerRequiredAcks,
-		MaxAttempts:  writerMaxAttempts,
-		Compression:  compress.Snappy,
-		ReadTimeout:  writerReadTimeout,
-		WriteTimeout: writerWriteTimeout,
+// Ready readiness check, the consumer group is not ready before it starts consuming and once it starts draining
+func (c *consumerGroup) Ready() error {
+	if !c.ready.Load() {
+		return ErrConsumerNotReady
 	}
-
-	return w
+	return nil
 }
 
-// ConsumeTopic start consumer group with given worker and pool size
+// ConsumeTopic start consumer group with given worker and pool size, blocks until ctx is done and the workers are drained.
+// Messages are dispatched to workers by key hash, so messages with the same key are processed sequentially
+// in fetch order while different keys are processed in parallel. A partition offset is committed only once
+// every message of the partition fetched before it is committed by its worker.
+// Workers do not use ctx, on shutdown they finish the fetched messages and commit them within the drain timeout.
 func (c *consumerGroup) ConsumeTopic(ctx context.Context, groupTopics []string, poolSize int, worker Worker) {
-	r := c.GetNewKafkaReader(c.Brokers, groupTopics, c.GroupID)
+	if poolSize < 1 {
+		poolSize = 1
+	}
+
+	instrumented := newInstrumentedReader(c.broker.NewReader(groupTopics, c.GroupID), c.GroupID)
+	r := newOrderedCommitReader(instrumented)
 
 	defer func() {
 		if err := r.Close(); err != nil {
@@ -81,10 +75,86 @@ func (c *consumerGroup) ConsumeTopic(ctx context.Context, groupTopics []string,
 
 	c.log.Infof("Starting consumer groupID: %s, topic: %+v, pool size: %v", c.GroupID, groupTopics, poolSize)
 
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+
+	queues := make([]chan kafka.Message, poolSize)
 	wg := &sync.WaitGroup{}
-	for i := 0; i <= poolSize; i++ {
+	for i := 0; i < poolSize; i++ {
+		queues[i] = make(chan kafka.Message, workerQueueSize)
 		wg.Add(1)
-		go worker(ctx, r, wg, i)
+		go func(workerID int, queue <-chan kafka.Message) {
+			defer wg.Done()
+			for m := range queue {
+				start := time.Now()
+				worker(workCtx, r, m, workerID)
+				observeHandler(c.GroupID, m, start)
+			}
+		}(i, queues[i])
+	}
+
+	go instrumented.runStats(workCtx)
+
+	c.ready.Store(true)
+	c.fetchMessages(ctx, r, queues)
+	c.ready.Store(false)
+
+	c.drain(queues, wg, cancelWork)
+}
+
+// fetchMessages dispatches fetched messages until ctx is done, fetch errors are retried with exponential backoff
+func (c *consumerGroup) fetchMessages(ctx context.Context, r Reader, queues []chan kafka.Message) {
+	fetchBackoff := newBackoff()
+	for {
+		m, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.log.Warnf("consumerGroup.FetchMessage: %v, retry in: %v", err, fetchBackoff.Delay())
+			if !fetchBackoff.Wait(ctx) {
+				return
+			}
+			continue
+		}
+		fetchBackoff.Reset()
+
+		queues[workerIndex(m, len(queues))] <- m
+	}
+}
+
+// drain lets the workers process the queued messages, in-flight messages are cancelled when the drain timeout expires
+func (c *consumerGroup) drain(queues []chan kafka.Message, wg *sync.WaitGroup, cancelWork context.CancelFunc) {
+	c.log.Infof("Draining consumer groupID: %s, timeout: %v", c.GroupID, c.drainTimeout)
+
+	for _, queue := range queues {
+		close(queue)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		c.log.Infof("Consumer groupID: %s drained", c.GroupID)
+	case <-time.After(c.drainTimeout):
+		c.log.Warnf("Consumer groupID: %s drain timeout, cancelling in-flight messages", c.GroupID)
+		cancelWork()
+		<-drained
+	}
+}
+
+// workerIndex messages without key are spread by partition to keep the partition order
+func workerIndex(m kafka.Message, poolSize int) int {
+	h := fnv.New32a()
+	if len(m.Key) > 0 {
+		_, _ = h.Write(m.Key)
+	} else {
+		_, _ = h.Write([]byte(m.Topic))
+		_, _ = h.Write([]byte{byte(m.Partition >> 24), byte(m.Partition >> 16), byte(m.Partition >> 8), byte(m.Partition)})
 	}
-	wg.Wait()
+	return int(h.Sum32() % uint32(poolSize))
 }
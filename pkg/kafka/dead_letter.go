@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Dead letter message headers
+const (
+	DeadLetterReasonHeader            = "dlq-reason"
+	DeadLetterStageHeader             = "dlq-stage"
+	DeadLetterOriginalTopicHeader     = "dlq-original-topic"
+	DeadLetterOriginalPartitionHeader = "dlq-original-partition"
+	DeadLetterOriginalOffsetHeader    = "dlq-original-offset"
+	DeadLetterAttemptsHeader          = "dlq-attempts"
+	DeadLetterFailedAtHeader          = "dlq-failed-at"
+)
+
+// Processing stages a message can fail at
+const (
+	StageUnmarshal  = "unmarshal"
+	StageValidation = "validation"
+	StageHandle     = "handle"
+)
+
+// NewDeadLetterMessage copies failed message key, value and headers to the dead letter topic and appends failure headers
+func NewDeadLetterMessage(m kafka.Message, topic string, stage string, reason error, attempts int) kafka.Message {
+	headers := make([]kafka.Header, 0, len(m.Headers)+7)
+	for _, header := range m.Headers {
+		if !isDeadLetterHeader(header.Key) {
+			headers = append(headers, header)
+		}
+	}
+
+	headers = append(headers,
+		kafka.Header{Key: DeadLetterReasonHeader, Value: []byte(reason.Error())},
+		kafka.Header{Key: DeadLetterStageHeader, Value: []byte(stage)},
+		kafka.Header{Key: DeadLetterOriginalTopicHeader, Value: []byte(m.Topic)},
+		kafka.Header{Key: DeadLetterOriginalPartitionHeader, Value: []byte(strconv.Itoa(m.Partition))},
+		kafka.Header{Key: DeadLetterOriginalOffsetHeader, Value: []byte(strconv.FormatInt(m.Offset, 10))},
+		kafka.Header{Key: DeadLetterAttemptsHeader, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: DeadLetterFailedAtHeader, Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	)
+
+	return kafka.Message{
+		Topic:   topic,
+		Key:     m.Key,
+		Value:   m.Value,
+		Headers: headers,
+		Time:    time.Now().UTC(),
+	}
+}
+
+func isDeadLetterHeader(key string) bool {
+	switch key {
+	case DeadLetterReasonHeader,
+		DeadLetterStageHeader,
+		DeadLetterOriginalTopicHeader,
+		DeadLetterOriginalPartitionHeader,
+		DeadLetterOriginalOffsetHeader,
+		DeadLetterAttemptsHeader,
+		DeadLetterFailedAtHeader:
+		return true
+	}
+	return false
+}
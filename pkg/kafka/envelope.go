@@ -0,0 +1,212 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/identity"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// Envelope message headers
+const (
+	EventIDHeader       = "event-id"
+	EventTypeHeader     = "event-type"
+	SchemaVersionHeader = "schema-version"
+	AggregateIDHeader   = "aggregate-id"
+	OccurredAtHeader    = "occurred-at"
+	ProducerHeader      = "producer"
+	CorrelationIDHeader = "correlation-id"
+	CommandIDHeader     = "command-id"
+	CallerSubjectHeader = "caller-subject"
+	CallerRolesHeader   = "caller-roles"
+)
+
+var (
+	ErrMissingEnvelope = errors.New("message has no envelope headers")
+)
+
+type correlationIDKey struct{}
+
+// Envelope event metadata carried in kafka message headers next to the proto payload
+type Envelope struct {
+	EventID       string
+	EventType     string
+	SchemaVersion int
+	AggregateID   string
+	OccurredAt    time.Time
+	Producer      string
+	CorrelationID string
+	CommandID     string
+	CallerSubject string
+	CallerRoles   []string
+}
+
+// NewEnvelope creates envelope with a new event id occurred now, correlation id is taken from ctx or defaults to the event id,
+// the caller is the identity stored in ctx
+func NewEnvelope(ctx context.Context, eventType string, schemaVersion int, aggregateID string, producer string) *Envelope {
+	eventID := uuid.NewV4().String()
+
+	correlationID := CorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		correlationID = eventID
+	}
+
+	envelope := &Envelope{
+		EventID:       eventID,
+		EventType:     eventType,
+		SchemaVersion: schemaVersion,
+		AggregateID:   aggregateID,
+		OccurredAt:    time.Now().UTC(),
+		Producer:      producer,
+		CorrelationID: correlationID,
+	}
+	if caller := identity.FromContext(ctx); caller != nil {
+		envelope.CallerSubject = caller.Subject
+		envelope.CallerRoles = caller.Roles
+	}
+	return envelope
+}
+
+// Headers returns envelope as kafka headers, the command id only when the message is a tracked command
+// and the caller only when the message was published for an authenticated caller
+func (e *Envelope) Headers() []kafka.Header {
+	headers := []kafka.Header{
+		{Key: EventIDHeader, Value: []byte(e.EventID)},
+		{Key: EventTypeHeader, Value: []byte(e.EventType)},
+		{Key: SchemaVersionHeader, Value: []byte(strconv.Itoa(e.SchemaVersion))},
+		{Key: AggregateIDHeader, Value: []byte(e.AggregateID)},
+		{Key: OccurredAtHeader, Value: []byte(e.OccurredAt.Format(time.RFC3339Nano))},
+		{Key: ProducerHeader, Value: []byte(e.Producer)},
+		{Key: CorrelationIDHeader, Value: []byte(e.CorrelationID)},
+	}
+	if e.CommandID != "" {
+		headers = append(headers, kafka.Header{Key: CommandIDHeader, Value: []byte(e.CommandID)})
+	}
+	if e.CallerSubject != "" {
+		headers = append(headers,
+			kafka.Header{Key: CallerSubjectHeader, Value: []byte(e.CallerSubject)},
+			kafka.Header{Key: CallerRolesHeader, Value: []byte(strings.Join(e.CallerRoles, ","))},
+		)
+	}
+	return headers
+}
+
+// WithEnvelope returns headers with the envelope headers replaced
+func WithEnvelope(headers []kafka.Header, e *Envelope) []kafka.Header {
+	for _, header := range e.Headers() {
+		headers = setHeader(headers, header.Key, string(header.Value))
+	}
+	return headers
+}
+
+// ParseEnvelope decodes envelope from message headers, returns ErrMissingEnvelope for messages published without it
+func ParseEnvelope(headers []kafka.Header) (*Envelope, error) {
+	eventType := getHeader(headers, EventTypeHeader)
+	if eventType == "" {
+		return nil, ErrMissingEnvelope
+	}
+
+	schemaVersion, err := strconv.Atoi(getHeader(headers, SchemaVersionHeader))
+	if err != nil {
+		return nil, errors.Wrap(err, "schema version")
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339Nano, getHeader(headers, OccurredAtHeader))
+	if err != nil {
+		return nil, errors.Wrap(err, "occurred at")
+	}
+
+	var callerRoles []string
+	if roles := getHeader(headers, CallerRolesHeader); roles != "" {
+		callerRoles = strings.Split(roles, ",")
+	}
+
+	return &Envelope{
+		EventID:       getHeader(headers, EventIDHeader),
+		EventType:     eventType,
+		SchemaVersion: schemaVersion,
+		AggregateID:   getHeader(headers, AggregateIDHeader),
+		OccurredAt:    occurredAt,
+		Producer:      getHeader(headers, ProducerHeader),
+		CorrelationID: getHeader(headers, CorrelationIDHeader),
+		CommandID:     getHeader(headers, CommandIDHeader),
+		CallerSubject: getHeader(headers, CallerSubjectHeader),
+		CallerRoles:   callerRoles,
+	}, nil
+}
+
+// MessageEnvelope parses message envelope, legacy messages published without it get
+// the fallback event type at schema version 1
+func MessageEnvelope(m kafka.Message, fallbackEventType string) (*Envelope, error) {
+	envelope, err := ParseEnvelope(m.Headers)
+	if err == nil {
+		return envelope, nil
+	}
+	if !errors.Is(err, ErrMissingEnvelope) || fallbackEventType == "" {
+		return nil, err
+	}
+
+	return &Envelope{
+		EventType:     fallbackEventType,
+		SchemaVersion: 1,
+		AggregateID:   string(m.Key),
+		OccurredAt:    m.Time,
+	}, nil
+}
+
+// ContextWithCorrelationID stores correlation id propagated to the envelopes of published messages
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
+
+// Upcaster transforms payload of a schema version into the payload of the next schema version
+type Upcaster func(value []byte) ([]byte, error)
+
+// Upcasters upcasters by event type and the schema version they upcast from
+type Upcasters map[string]map[int]Upcaster
+
+// Upcast applies upcasters one version at a time until there is no upcaster for the reached version,
+// returns the upcasted value and its schema version
+func (u Upcasters) Upcast(eventType string, schemaVersion int, value []byte) ([]byte, int, error) {
+	for {
+		upcaster, ok := u[eventType][schemaVersion]
+		if !ok {
+			return value, schemaVersion, nil
+		}
+
+		upcasted, err := upcaster(value)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "upcast %s v%d", eventType, schemaVersion)
+		}
+
+		value = upcasted
+		schemaVersion++
+	}
+}
+
+// UpcastMessage upcasts message value described by the envelope, the envelope headers are updated
+// so retried and dead lettered messages are not upcasted twice
+func (u Upcasters) UpcastMessage(m kafka.Message, e *Envelope) (kafka.Message, error) {
+	value, schemaVersion, err := u.Upcast(e.EventType, e.SchemaVersion, m.Value)
+	if err != nil {
+		return m, err
+	}
+
+	if schemaVersion != e.SchemaVersion {
+		e.SchemaVersion = schemaVersion
+		m.Value = value
+		m.Headers = WithEnvelope(m.Headers, e)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,115 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+)
+
+// FailureMetrics service counters of failed messages
+type FailureMetrics struct {
+	Error     prometheus.Counter
+	Retry     prometheus.Counter
+	DlqFailed prometheus.Counter
+}
+
+// DeadLetteredFunc called once a failed message is dead lettered, before it is committed
+type DeadLetteredFunc func(ctx context.Context, m kafka.Message, stage string, reason error)
+
+// FailureHandler dead letters or retries messages a consumer failed to process
+type FailureHandler interface {
+	CommitErrMessage(ctx context.Context, r Reader, m kafka.Message, stage string, reason error, attempts int)
+	RetryErrMessage(ctx context.Context, r Reader, m kafka.Message, reason error)
+}
+
+type failureHandler struct {
+	log          logger.Logger
+	retry        RetryConfig
+	routes       TopicRoutes
+	producer     Producer
+	metrics      FailureMetrics
+	deadLettered DeadLetteredFunc
+}
+
+// NewFailureHandler failure handler constructor, deadLettered is optional
+func NewFailureHandler(log logger.Logger, cfg *Config, routes TopicRoutes, producer Producer, metrics FailureMetrics, deadLettered DeadLetteredFunc) *failureHandler {
+	return &failureHandler{log: log, retry: cfg.Retry, routes: routes, producer: producer, metrics: metrics, deadLettered: deadLettered}
+}
+
+// CommitErrMessage forwards failed message to the topic dead letter topic and commits it. The dead letter publish
+// is retried until it succeeds, so the message never holds back the commits of its partition, a message failed
+// because of shutdown is left uncommitted to be redelivered.
+func (f *failureHandler) CommitErrMessage(ctx context.Context, r Reader, m kafka.Message, stage string, reason error, attempts int) {
+	f.metrics.Error.Inc()
+	if ctx.Err() != nil {
+		return
+	}
+
+	if dlqTopic := f.routes.DeadLetterTopic(m.Topic); dlqTopic != "" {
+		dlqMessage := NewDeadLetterMessage(m, dlqTopic, stage, reason, attempts)
+		if !f.publish(ctx, dlqMessage, f.metrics.DlqFailed) {
+			return
+		}
+	}
+
+	if f.deadLettered != nil {
+		f.deadLettered(ctx, m, stage, reason)
+	}
+	f.commit(ctx, r, m)
+}
+
+// RetryErrMessage re-publishes failed message to the next delayed retry tier and commits it,
+// when the tiers are exhausted the message goes to the dead letter topic. Like the dead letter publish
+// the retry publish is retried until it succeeds or ctx is done.
+// The retried message is redelivered to its original topic behind newer messages of the same key, so consumers
+// relying on key order must guard against applying it out of order, e.g. by an expected version.
+func (f *failureHandler) RetryErrMessage(ctx context.Context, r Reader, m kafka.Message, reason error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	retryMessage, ok := f.retry.NewRetryMessage(m, reason)
+	if !ok {
+		f.CommitErrMessage(ctx, r, m, StageHandle, reason, GetRetryAttempt(m)+1)
+		return
+	}
+
+	if !f.publish(ctx, retryMessage, nil) {
+		return
+	}
+
+	f.metrics.Retry.Inc()
+	f.commit(ctx, r, m)
+}
+
+// publish publishes the message until it succeeds, returns false when ctx is done first. Failed attempts are
+// counted by failed when set.
+func (f *failureHandler) publish(ctx context.Context, m kafka.Message, failed prometheus.Counter) bool {
+	publishBackoff := newBackoff()
+	for {
+		err := f.producer.PublishMessage(ctx, m)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if failed != nil {
+			failed.Inc()
+		}
+		f.log.Warnf("failureHandler.PublishMessage: %v, topic: %s, retry in: %v", err, m.Topic, publishBackoff.Delay())
+		if !publishBackoff.Wait(ctx) {
+			return false
+		}
+	}
+}
+
+func (f *failureHandler) commit(ctx context.Context, r Reader, m kafka.Message) {
+	f.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
+	if err := r.CommitMessages(ctx, m); err != nil {
+		f.log.WarnMsg("commitMessage", err)
+	}
+}
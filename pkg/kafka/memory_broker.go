@@ -0,0 +1,325 @@
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	memoryTopicPartitions = 3
+)
+
+var (
+	sharedMemoryBroker = NewMemoryBroker()
+)
+
+type topicPartition struct {
+	topic     string
+	partition int
+}
+
+type memoryGroup struct {
+	committed  map[topicPartition]int64
+	members    []*memoryReader
+	generation int
+}
+
+type memoryBroker struct {
+	mu      sync.Mutex
+	topics  map[string][][]kafka.Message
+	groups  map[string]*memoryGroup
+	changed chan struct{}
+}
+
+// NewMemoryBroker in-memory broker keeping topics, partitions, consumer group offsets and commits in the process,
+// partitions are chosen by key hash and consumer group members split the partitions of their topics
+func NewMemoryBroker() *memoryBroker {
+	return &memoryBroker{
+		topics:  make(map[string][][]kafka.Message),
+		groups:  make(map[string]*memoryGroup),
+		changed: make(chan struct{}),
+	}
+}
+
+func (b *memoryBroker) NewProducer() Producer {
+	return &memoryProducer{broker: b}
+}
+
+// NewReader joins the consumer group, group partitions are reassigned between the members
+func (b *memoryBroker) NewReader(groupTopics []string, groupID string) Reader {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group, ok := b.groups[groupID]
+	if !ok {
+		group = &memoryGroup{committed: make(map[topicPartition]int64)}
+		b.groups[groupID] = group
+	}
+
+	r := &memoryReader{broker: b, group: group, topics: groupTopics, positions: make(map[topicPartition]int64), generation: -1}
+	for _, topic := range groupTopics {
+		b.createTopic(topic, memoryTopicPartitions)
+	}
+	group.members = append(group.members, r)
+	group.generation++
+	b.notify()
+
+	return r
+}
+
+func (b *memoryBroker) CreateTopics(ctx context.Context, topics ...kafka.TopicConfig) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		b.createTopic(topic.Topic, topic.NumPartitions)
+	}
+	return nil
+}
+
+// EndOffsets offsets the next messages published to the topic partitions get, by topic and partition
+func (b *memoryBroker) EndOffsets(ctx context.Context, topics ...string) (map[string]map[int]int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	offsets := make(map[string]map[int]int64, len(topics))
+	for _, topic := range topics {
+		offsets[topic] = make(map[int]int64)
+		for partition, msgs := range b.topics[topic] {
+			offsets[topic][partition] = int64(len(msgs))
+		}
+	}
+	return offsets, nil
+}
+
+func (b *memoryBroker) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close keeps the messages, the broker lives as long as the process
+func (b *memoryBroker) Close() error {
+	return nil
+}
+
+func (b *memoryBroker) publish(msgs ...kafka.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, m := range msgs {
+		if m.Topic == "" {
+			return errors.New("memoryBroker: message without topic")
+		}
+
+		partitions := b.createTopic(m.Topic, memoryTopicPartitions)
+		m.Partition = partitionByKey(m.Key, len(partitions))
+		m.Offset = int64(len(partitions[m.Partition]))
+		if m.Time.IsZero() {
+			m.Time = time.Now().UTC()
+		}
+		m.Key = append([]byte(nil), m.Key...)
+		m.Value = append([]byte(nil), m.Value...)
+		m.Headers = append([]kafka.Header(nil), m.Headers...)
+
+		b.topics[m.Topic][m.Partition] = append(partitions[m.Partition], m)
+	}
+
+	b.notify()
+	return nil
+}
+
+func (b *memoryBroker) createTopic(topic string, numPartitions int) [][]kafka.Message {
+	if partitions, ok := b.topics[topic]; ok {
+		return partitions
+	}
+	if numPartitions <= 0 {
+		numPartitions = memoryTopicPartitions
+	}
+
+	partitions := make([][]kafka.Message, numPartitions)
+	b.topics[topic] = partitions
+	return partitions
+}
+
+// notify wakes up the readers waiting for messages or a rebalance, must be called with the lock held
+func (b *memoryBroker) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// partitionByKey messages without key go to the first partition to keep their order
+func partitionByKey(key []byte, numPartitions int) int {
+	if len(key) == 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int(h.Sum32() % uint32(numPartitions))
+}
+
+type memoryProducer struct {
+	broker *memoryBroker
+}
+
+func (p *memoryProducer) PublishMessage(ctx context.Context, msgs ...kafka.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.broker.publish(msgs...)
+}
+
+func (p *memoryProducer) Close() error {
+	return nil
+}
+
+type memoryReader struct {
+	broker     *memoryBroker
+	group      *memoryGroup
+	topics     []string
+	assigned   []topicPartition
+	positions  map[topicPartition]int64
+	generation int
+	rebalances int64
+	next       int
+	closed     bool
+}
+
+// FetchMessage returns the next message of the assigned partitions, blocks until there is one or ctx is done
+func (r *memoryReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		r.broker.mu.Lock()
+		if r.closed {
+			r.broker.mu.Unlock()
+			return kafka.Message{}, io.EOF
+		}
+
+		r.rebalance()
+		if m, ok := r.nextMessage(); ok {
+			r.broker.mu.Unlock()
+			return m, nil
+		}
+		changed := r.broker.changed
+		r.broker.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// CommitMessages commits the offsets following the messages for the group
+func (r *memoryReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.broker.mu.Lock()
+	defer r.broker.mu.Unlock()
+
+	for _, m := range msgs {
+		tp := topicPartition{topic: m.Topic, partition: m.Partition}
+		if m.Offset+1 > r.group.committed[tp] {
+			r.group.committed[tp] = m.Offset + 1
+		}
+	}
+	return nil
+}
+
+// Close leaves the consumer group, its partitions are reassigned to the other members
+func (r *memoryReader) Close() error {
+	r.broker.mu.Lock()
+	defer r.broker.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	members := r.group.members[:0]
+	for _, member := range r.group.members {
+		if member != r {
+			members = append(members, member)
+		}
+	}
+	r.group.members = members
+	r.group.generation++
+	r.broker.notify()
+
+	return nil
+}
+
+// rebalance assigns partitions round robin between the group members subscribed to the topic,
+// fetching resumes from the committed offsets, must be called with the lock held
+func (r *memoryReader) rebalance() {
+	if r.generation == r.group.generation {
+		return
+	}
+	r.generation = r.group.generation
+	r.rebalances++
+
+	r.assigned = r.assigned[:0]
+	r.positions = make(map[topicPartition]int64)
+
+	topics := append([]string(nil), r.topics...)
+	sort.Strings(topics)
+	for _, topic := range topics {
+		subscribers := make([]*memoryReader, 0, len(r.group.members))
+		for _, member := range r.group.members {
+			if member.subscribed(topic) {
+				subscribers = append(subscribers, member)
+			}
+		}
+
+		for partition := range r.broker.topics[topic] {
+			if subscribers[partition%len(subscribers)] != r {
+				continue
+			}
+			tp := topicPartition{topic: topic, partition: partition}
+			r.assigned = append(r.assigned, tp)
+			r.positions[tp] = r.group.committed[tp]
+		}
+	}
+}
+
+// nextMessage takes partitions in turns so one busy partition does not starve the others, must be called with the lock held
+func (r *memoryReader) nextMessage() (kafka.Message, bool) {
+	for i := 0; i < len(r.assigned); i++ {
+		tp := r.assigned[(r.next+i)%len(r.assigned)]
+		partition := r.broker.topics[tp.topic][tp.partition]
+		position := r.positions[tp]
+		if position >= int64(len(partition)) {
+			continue
+		}
+
+		r.positions[tp] = position + 1
+		r.next = (r.next + i + 1) % len(r.assigned)
+
+		m := partition[position]
+		m.HighWaterMark = int64(len(partition))
+		return m, true
+	}
+	return kafka.Message{}, false
+}
+
+// Stats reports rebalances since the previous call
+func (r *memoryReader) Stats() kafka.ReaderStats {
+	r.broker.mu.Lock()
+	defer r.broker.mu.Unlock()
+
+	stats := kafka.ReaderStats{Rebalances: r.rebalances}
+	r.rebalances = 0
+	return stats
+}
+
+func (r *memoryReader) subscribed(topic string) bool {
+	for _, t := range r.topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
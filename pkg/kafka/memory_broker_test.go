@@ -0,0 +1,243 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	testTopic   = "products"
+	testGroupID = "products_group"
+	testTimeout = 2 * time.Second
+)
+
+func publishTestMessages(t *testing.T, b *memoryBroker, keys ...string) {
+	t.Helper()
+	producer := b.NewProducer()
+	for i, key := range keys {
+		m := kafka.Message{
+			Topic:   testTopic,
+			Key:     []byte(key),
+			Value:   []byte(fmt.Sprintf("%s-%d", key, i)),
+			Headers: []kafka.Header{{Key: "event-type", Value: []byte("ProductCreated")}},
+		}
+		if err := producer.PublishMessage(context.Background(), m); err != nil {
+			t.Fatalf("PublishMessage: %v", err)
+		}
+	}
+}
+
+func fetchTestMessages(t *testing.T, r Reader, count int) []kafka.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	msgs := make([]kafka.Message, 0, count)
+	for len(msgs) < count {
+		m, err := r.FetchMessage(ctx)
+		if err != nil {
+			t.Fatalf("FetchMessage after %d of %d messages: %v", len(msgs), count, err)
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs
+}
+
+func assertNoMessage(t *testing.T, r Reader) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if m, err := r.FetchMessage(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected no message, got: %s/%d@%d, err: %v", m.Topic, m.Partition, m.Offset, err)
+	}
+}
+
+func TestMemoryBroker_KeyOrderAndHeaders(t *testing.T) {
+	b := NewMemoryBroker()
+	r := b.NewReader([]string{testTopic}, testGroupID)
+	defer r.Close()
+
+	publishTestMessages(t, b, "a", "b", "a", "c", "a")
+
+	var values []string
+	for _, m := range fetchTestMessages(t, r, 5) {
+		if string(m.Headers[0].Value) != "ProductCreated" {
+			t.Errorf("header not kept: %+v", m.Headers)
+		}
+		if m.HighWaterMark <= m.Offset {
+			t.Errorf("high water mark %d not after offset %d", m.HighWaterMark, m.Offset)
+		}
+		if string(m.Key) == "a" {
+			values = append(values, string(m.Value))
+		}
+	}
+
+	if fmt.Sprint(values) != "[a-0 a-2 a-4]" {
+		t.Fatalf("messages of one key out of order: %v", values)
+	}
+}
+
+func TestMemoryBroker_CommittedOffsetsResume(t *testing.T) {
+	b := NewMemoryBroker()
+	publishTestMessages(t, b, "a", "b", "c")
+
+	r := b.NewReader([]string{testTopic}, testGroupID)
+	msgs := fetchTestMessages(t, r, 3)
+	if err := r.CommitMessages(context.Background(), msgs[:2]...); err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r = b.NewReader([]string{testTopic}, testGroupID)
+	defer r.Close()
+
+	redelivered := fetchTestMessages(t, r, 1)[0]
+	if string(redelivered.Value) != string(msgs[2].Value) {
+		t.Fatalf("expected uncommitted %s to be redelivered, got %s", msgs[2].Value, redelivered.Value)
+	}
+	assertNoMessage(t, r)
+
+	other := b.NewReader([]string{testTopic}, "other_group")
+	defer other.Close()
+	fetchTestMessages(t, other, 3)
+}
+
+func TestMemoryBroker_GroupMembersSplitPartitions(t *testing.T) {
+	b := NewMemoryBroker()
+	first := b.NewReader([]string{testTopic}, testGroupID)
+	second := b.NewReader([]string{testTopic}, testGroupID)
+	defer first.Close()
+
+	keys := make([]string, 0, 30)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, fmt.Sprintf("key-%d", i))
+	}
+	publishTestMessages(t, b, keys...)
+
+	// partitions are assigned round robin in join order
+	firstCount, secondCount := 0, 0
+	for partition, msgs := range b.topics[testTopic] {
+		if partition%2 == 0 {
+			firstCount += len(msgs)
+		} else {
+			secondCount += len(msgs)
+		}
+	}
+
+	firstMsgs := fetchTestMessages(t, first, firstCount)
+	for _, m := range firstMsgs {
+		if m.Partition%2 != 0 {
+			t.Fatalf("first member fetched partition %d", m.Partition)
+		}
+	}
+	for _, m := range fetchTestMessages(t, second, secondCount) {
+		if m.Partition%2 != 1 {
+			t.Fatalf("second member fetched partition %d", m.Partition)
+		}
+	}
+	assertNoMessage(t, first)
+	assertNoMessage(t, second)
+
+	if err := first.CommitMessages(context.Background(), firstMsgs...); err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// the uncommitted messages of the member that left are redelivered to the remaining one
+	for _, m := range fetchTestMessages(t, first, secondCount) {
+		if m.Partition%2 != 1 {
+			t.Fatalf("expected partition of the member that left, got %d", m.Partition)
+		}
+	}
+	assertNoMessage(t, first)
+}
+
+func TestNewBroker_MemoryDriver(t *testing.T) {
+	allowed := memoryDriverAllowed.Load()
+	t.Cleanup(func() { memoryDriverAllowed.Store(allowed) })
+
+	cfg := &Config{Driver: DriverMemory}
+
+	memoryDriverAllowed.Store(false)
+	if _, err := NewBroker(nil, cfg); !errors.Is(err, ErrMemoryDriverNotAllowed) {
+		t.Fatalf("expected memory driver to be refused, got: %v", err)
+	}
+
+	AllowMemoryDriver()
+	broker, err := NewBroker(nil, cfg)
+	if err != nil {
+		t.Fatalf("NewBroker: %v", err)
+	}
+	if broker != sharedMemoryBroker {
+		t.Fatalf("expected the process shared memory broker")
+	}
+}
+
+func TestConsumerGroup_MemoryBroker(t *testing.T) {
+	b := NewMemoryBroker()
+	log := logger.NewAppLogger(logger.NewLoggerConfig("error", false, "json"))
+	log.InitLogger()
+
+	keys := make([]string, 0, 50)
+	expected := make(map[string][]string)
+	for i := 0; i < cap(keys); i++ {
+		key := fmt.Sprintf("key-%d", i%7)
+		keys = append(keys, key)
+		expected[key] = append(expected[key], fmt.Sprintf("%s-%d", key, i))
+	}
+	publishTestMessages(t, b, keys...)
+
+	var mu sync.Mutex
+	processed := make(map[string][]string)
+	total := 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewConsumerGroup(b, &Config{GroupID: testGroupID}, log).ConsumeTopic(ctx, []string{testTopic}, 4, func(ctx context.Context, r Reader, m kafka.Message, workerID int) {
+			if err := r.CommitMessages(ctx, m); err != nil {
+				t.Errorf("CommitMessages: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			processed[string(m.Key)] = append(processed[string(m.Key)], string(m.Value))
+			if total++; total == len(keys) {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(testTimeout):
+		cancel()
+		<-done
+		t.Fatalf("consumer group processed %d of %d messages", total, len(keys))
+	}
+
+	for key, values := range expected {
+		if fmt.Sprint(processed[key]) != fmt.Sprint(values) {
+			t.Fatalf("messages of %s out of order: %v", key, processed[key])
+		}
+	}
+
+	r := b.NewReader([]string{testTopic}, testGroupID)
+	defer r.Close()
+	assertNoMessage(t, r)
+}
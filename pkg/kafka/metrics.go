@@ -0,0 +1,199 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	statsInterval = 10 * time.Second
+
+	// maxFetchedOffsets bounds fetch times kept per partition while its commits are held back by an uncommitted message
+	maxFetchedOffsets = 10000
+)
+
+// Consumer group metrics are shared by the groups of the process and labeled by group
+var (
+	consumerMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_messages_total",
+		Help: "The total number of fetched messages",
+	}, []string{"group", "topic"})
+
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "The number of messages between the partition committed offset and its high water mark",
+	}, []string{"group", "topic", "partition"})
+
+	consumerFetchToCommit = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_consumer_fetch_to_commit_seconds",
+		Help:    "The time from fetching a message to committing it",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 15),
+	}, []string{"group", "topic"})
+
+	consumerHandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_consumer_handler_duration_seconds",
+		Help:    "The time a worker spends processing a message",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"group", "topic"})
+
+	consumerRebalances = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_rebalances_total",
+		Help: "The total number of consumer group rebalances",
+	}, []string{"group"})
+)
+
+// statsReader readers reporting stats since the previous call, implemented by *kafka.Reader and the in-memory broker reader
+type statsReader interface {
+	Stats() kafka.ReaderStats
+}
+
+type fetchedOffset struct {
+	offset    int64
+	fetchedAt time.Time
+}
+
+// partitionLag the partition high water mark of the last fetched message and the last committed offset
+type partitionLag struct {
+	highWaterMark int64
+	committed     int64
+}
+
+func (l *partitionLag) lag() float64 {
+	if lag := l.highWaterMark - l.committed - 1; lag > 0 {
+		return float64(lag)
+	}
+	return 0
+}
+
+// instrumentedReader tracks fetch time of the messages to report fetch to commit latency, a commit of a partition
+// offset commits the messages fetched before it too. The lag of each partition is its high water mark minus the
+// committed offset, so a partition whose commits are held back keeps growing its own lag.
+type instrumentedReader struct {
+	Reader
+	groupID string
+	mu      sync.Mutex
+	fetched map[topicPartition][]fetchedOffset
+	lags    map[topicPartition]*partitionLag
+}
+
+func newInstrumentedReader(r Reader, groupID string) *instrumentedReader {
+	return &instrumentedReader{
+		Reader:  r,
+		groupID: groupID,
+		fetched: make(map[topicPartition][]fetchedOffset),
+		lags:    make(map[topicPartition]*partitionLag),
+	}
+}
+
+// FetchMessage records the fetch time, an offset that is not after the tracked ones means the partition is fetched
+// again from the committed offset after a rebalance, so the fetch times of the partition are dropped
+func (r *instrumentedReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	m, err := r.Reader.FetchMessage(ctx)
+	if err != nil {
+		return m, err
+	}
+
+	consumerMessages.WithLabelValues(r.groupID, m.Topic).Inc()
+
+	tp := topicPartition{topic: m.Topic, partition: m.Partition}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fetched := r.fetched[tp]
+	if len(fetched) > 0 && m.Offset <= fetched[len(fetched)-1].offset {
+		fetched = nil
+	}
+	if len(fetched) >= maxFetchedOffsets {
+		fetched = fetched[1:]
+	}
+	r.fetched[tp] = append(fetched, fetchedOffset{offset: m.Offset, fetchedAt: time.Now()})
+
+	// the partition is consumed from the committed offset, the first fetched message is the one after it
+	lag, ok := r.lags[tp]
+	if !ok || m.Offset <= lag.committed {
+		lag = &partitionLag{committed: m.Offset - 1}
+		r.lags[tp] = lag
+	}
+	lag.highWaterMark = m.HighWaterMark
+	r.setLag(tp, lag)
+
+	return m, nil
+}
+
+func (r *instrumentedReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if err := r.Reader.CommitMessages(ctx, msgs...); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range msgs {
+		tp := topicPartition{topic: m.Topic, partition: m.Partition}
+		fetched := r.fetched[tp]
+		for len(fetched) > 0 && fetched[0].offset <= m.Offset {
+			consumerFetchToCommit.WithLabelValues(r.groupID, m.Topic).Observe(time.Since(fetched[0].fetchedAt).Seconds())
+			fetched = fetched[1:]
+		}
+		if len(fetched) == 0 {
+			delete(r.fetched, tp)
+		} else {
+			r.fetched[tp] = fetched
+		}
+
+		if lag, ok := r.lags[tp]; ok && m.Offset > lag.committed {
+			lag.committed = m.Offset
+			r.setLag(tp, lag)
+		}
+	}
+
+	return nil
+}
+
+// Close removes the lag of the partitions the reader consumed, another group member reports them after the rebalance
+func (r *instrumentedReader) Close() error {
+	r.mu.Lock()
+	for tp := range r.lags {
+		consumerLag.DeleteLabelValues(r.groupID, tp.topic, strconv.Itoa(tp.partition))
+	}
+	r.lags = make(map[topicPartition]*partitionLag)
+	r.mu.Unlock()
+
+	return r.Reader.Close()
+}
+
+func (r *instrumentedReader) setLag(tp topicPartition, lag *partitionLag) {
+	consumerLag.WithLabelValues(r.groupID, tp.topic, strconv.Itoa(tp.partition)).Set(lag.lag())
+}
+
+// runStats collects the reader rebalances until ctx is done
+func (r *instrumentedReader) runStats(ctx context.Context) {
+	stats, ok := r.Reader.(statsReader)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			consumerRebalances.WithLabelValues(r.groupID).Add(float64(stats.Stats().Rebalances))
+		}
+	}
+}
+
+// observeHandler reports worker processing time of the message
+func observeHandler(groupID string, m kafka.Message, start time.Time) {
+	consumerHandlerDuration.WithLabelValues(groupID, m.Topic).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,113 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type topicPartitionOffsets struct {
+	pending []int64
+	done    map[int64]bool
+}
+
+// orderedCommitReader workers complete messages of a partition out of order, the reader tracks the in-flight offsets
+// and commits only the highest offset below which every fetched message of the partition was completed
+type orderedCommitReader struct {
+	Reader
+	mu         sync.Mutex
+	partitions map[topicPartition]*topicPartitionOffsets
+	commitMu   sync.Mutex
+	committed  map[topicPartition]int64
+}
+
+func newOrderedCommitReader(r Reader) *orderedCommitReader {
+	return &orderedCommitReader{
+		Reader:     r,
+		partitions: make(map[topicPartition]*topicPartitionOffsets),
+		committed:  make(map[topicPartition]int64),
+	}
+}
+
+// FetchMessage tracks the fetched offset as in-flight, an offset that is not after the tracked ones means the
+// partition was reassigned and is fetched again from the committed offset, so its tracking starts over
+func (r *orderedCommitReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	m, err := r.Reader.FetchMessage(ctx)
+	if err != nil {
+		return m, err
+	}
+
+	tp := topicPartition{topic: m.Topic, partition: m.Partition}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	offsets, ok := r.partitions[tp]
+	if !ok || (len(offsets.pending) > 0 && m.Offset <= offsets.pending[len(offsets.pending)-1]) {
+		offsets = &topicPartitionOffsets{done: make(map[int64]bool)}
+		r.partitions[tp] = offsets
+		r.resetCommitted(tp, m.Offset)
+	}
+	offsets.pending = append(offsets.pending, m.Offset)
+
+	return m, nil
+}
+
+// CommitMessages marks the messages completed and commits the partitions contiguous completed offsets
+func (r *orderedCommitReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	var commits []kafka.Message
+	for _, m := range msgs {
+		if offset, ok := r.complete(m); ok {
+			commit := m
+			commit.Offset = offset
+			commits = append(commits, commit)
+		}
+	}
+
+	r.commitMu.Lock()
+	defer r.commitMu.Unlock()
+
+	for _, m := range commits {
+		tp := topicPartition{topic: m.Topic, partition: m.Partition}
+		if committed, ok := r.committed[tp]; ok && m.Offset <= committed {
+			continue
+		}
+		if err := r.Reader.CommitMessages(ctx, m); err != nil {
+			return err
+		}
+		r.committed[tp] = m.Offset
+	}
+	return nil
+}
+
+// complete returns the highest contiguous completed offset of the message partition if it advanced
+func (r *orderedCommitReader) complete(m kafka.Message) (int64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	offsets, ok := r.partitions[topicPartition{topic: m.Topic, partition: m.Partition}]
+	if !ok || len(offsets.pending) == 0 || m.Offset < offsets.pending[0] {
+		return 0, false
+	}
+	offsets.done[m.Offset] = true
+
+	var committable int64
+	advanced := false
+	for len(offsets.pending) > 0 && offsets.done[offsets.pending[0]] {
+		committable = offsets.pending[0]
+		delete(offsets.done, committable)
+		offsets.pending = offsets.pending[1:]
+		advanced = true
+	}
+	return committable, advanced
+}
+
+func (r *orderedCommitReader) resetCommitted(tp topicPartition, offset int64) {
+	r.commitMu.Lock()
+	defer r.commitMu.Unlock()
+
+	if committed, ok := r.committed[tp]; ok && offset <= committed {
+		delete(r.committed, tp)
+	}
+}
@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+)
+
+func groupCommitted(b *memoryBroker, tp topicPartition) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.groups[testGroupID].committed[tp]
+}
+
+// forceRebalance rewinds the group offset of the partition and makes the members fetch again from it
+func forceRebalance(b *memoryBroker, tp topicPartition, committed int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	group := b.groups[testGroupID]
+	group.committed[tp] = committed
+	group.generation++
+	b.notify()
+}
+
+func TestOrderedCommitReader_CommitsContiguousPrefix(t *testing.T) {
+	b := NewMemoryBroker()
+	publishTestMessages(t, b, "a", "a", "a", "a", "a")
+
+	r := newOrderedCommitReader(b.NewReader([]string{testTopic}, testGroupID))
+	defer r.Close()
+
+	msgs := fetchTestMessages(t, r, 5)
+	tp := topicPartition{topic: testTopic, partition: msgs[0].Partition}
+
+	steps := []struct {
+		complete  int
+		committed int64
+	}{
+		{complete: 1, committed: 0},
+		{complete: 3, committed: 0},
+		{complete: 0, committed: 2},
+		{complete: 4, committed: 2},
+		{complete: 2, committed: 5},
+	}
+	for _, step := range steps {
+		if err := r.CommitMessages(context.Background(), msgs[step.complete]); err != nil {
+			t.Fatalf("CommitMessages: %v", err)
+		}
+		if committed := groupCommitted(b, tp); committed != step.committed {
+			t.Fatalf("after completing offset %d committed offset is %d, want %d", msgs[step.complete].Offset, committed, step.committed)
+		}
+	}
+}
+
+func TestOrderedCommitReader_RebalanceStartsOver(t *testing.T) {
+	b := NewMemoryBroker()
+	publishTestMessages(t, b, "a", "a", "a", "a", "a", "a")
+
+	r := newOrderedCommitReader(b.NewReader([]string{testTopic}, testGroupID))
+	defer r.Close()
+
+	msgs := fetchTestMessages(t, r, 6)
+	tp := topicPartition{topic: testTopic, partition: msgs[0].Partition}
+
+	if err := r.CommitMessages(context.Background(), msgs[:4]...); err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+	if committed := groupCommitted(b, tp); committed != 4 {
+		t.Fatalf("committed offset is %d, want 4", committed)
+	}
+	if err := r.CommitMessages(context.Background(), msgs[5]); err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+
+	// the group offset moved back behind the commits of the reader, e.g. the partition was reset
+	forceRebalance(b, tp, 2)
+
+	refetched := fetchTestMessages(t, r, 4)
+	if refetched[0].Offset != 2 {
+		t.Fatalf("refetched from offset %d, want 2", refetched[0].Offset)
+	}
+
+	// completions before the rebalance are forgotten, the refetched offsets are committed again
+	if err := r.CommitMessages(context.Background(), refetched[3]); err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+	if committed := groupCommitted(b, tp); committed != 2 {
+		t.Fatalf("committed offset is %d after an out of order completion, want 2", committed)
+	}
+	if err := r.CommitMessages(context.Background(), refetched[0]); err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+	if committed := groupCommitted(b, tp); committed != 3 {
+		t.Fatalf("committed offset is %d, want 3", committed)
+	}
+	if err := r.CommitMessages(context.Background(), refetched[1], refetched[2]); err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+	if committed := groupCommitted(b, tp); committed != 6 {
+		t.Fatalf("committed offset is %d, want 6", committed)
+	}
+}
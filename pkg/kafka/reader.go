@@ -25,3 +25,23 @@ func NewKafkaReader(kafkaURL []string, topic, groupID string, errLogger kafka.Lo
 		},
 	})
 }
+
+// NewKafkaGroupReader create new configured kafka reader of the group topics
+func NewKafkaGroupReader(kafkaURL []string, groupTopics []string, groupID string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:                kafkaURL,
+		GroupID:                groupID,
+		GroupTopics:            groupTopics,
+		MinBytes:               minBytes,
+		MaxBytes:               maxBytes,
+		QueueCapacity:          queueCapacity,
+		HeartbeatInterval:      heartbeatInterval,
+		CommitInterval:         commitInterval,
+		PartitionWatchInterval: partitionWatchInterval,
+		MaxAttempts:            maxAttempts,
+		MaxWait:                maxWait,
+		Dialer: &kafka.Dialer{
+			Timeout: dialTimeout,
+		},
+	})
+}
@@ -0,0 +1,222 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/segmentio/kafka-go"
+)
+
+// Retry message headers
+const (
+	RetryAttemptHeader       = "retry-attempt"
+	RetryOriginalTopicHeader = "retry-original-topic"
+	RetryNotBeforeHeader     = "retry-not-before"
+	RetryReasonHeader        = "retry-reason"
+)
+
+// RetryConfig delayed retry tiers, a failed message goes through every tier before the dead letter topic
+type RetryConfig struct {
+	Tiers             []time.Duration `mapstructure:"tiers"`
+	Partitions        int             `mapstructure:"partitions"`
+	ReplicationFactor int             `mapstructure:"replicationFactor"`
+}
+
+// RetryTopicName returns retry topic name for the tier delay, e.g. product_create.retry.5s
+func RetryTopicName(topic string, delay time.Duration) string {
+	switch {
+	case delay%time.Hour == 0:
+		return fmt.Sprintf("%s.retry.%dh", topic, delay/time.Hour)
+	case delay%time.Minute == 0:
+		return fmt.Sprintf("%s.retry.%dm", topic, delay/time.Minute)
+	default:
+		return fmt.Sprintf("%s.retry.%ds", topic, delay/time.Second)
+	}
+}
+
+// RetryTopics returns retry topic names of the tier for each topic
+func (c *RetryConfig) RetryTopics(topics []string, delay time.Duration) []string {
+	retryTopics := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		retryTopics = append(retryTopics, RetryTopicName(topic, delay))
+	}
+	return retryTopics
+}
+
+// TopicConfigs returns retry topics of all tiers to create
+func (c *RetryConfig) TopicConfigs(topics []string) []kafka.TopicConfig {
+	topicConfigs := make([]kafka.TopicConfig, 0, len(topics)*len(c.Tiers))
+	for _, delay := range c.Tiers {
+		for _, retryTopic := range c.RetryTopics(topics, delay) {
+			topicConfigs = append(topicConfigs, kafka.TopicConfig{
+				Topic:             retryTopic,
+				NumPartitions:     c.Partitions,
+				ReplicationFactor: c.ReplicationFactor,
+			})
+		}
+	}
+	return topicConfigs
+}
+
+// GetRetryAttempt returns how many times message was already retried
+func GetRetryAttempt(m kafka.Message) int {
+	attempt, err := strconv.Atoi(getHeader(m.Headers, RetryAttemptHeader))
+	if err != nil {
+		return 0
+	}
+	return attempt
+}
+
+// NewRetryMessage builds message for the next retry tier, returns false when all tiers are exhausted
+func (c *RetryConfig) NewRetryMessage(m kafka.Message, reason error) (kafka.Message, bool) {
+	attempt := GetRetryAttempt(m)
+	if attempt >= len(c.Tiers) {
+		return kafka.Message{}, false
+	}
+
+	originalTopic := getHeader(m.Headers, RetryOriginalTopicHeader)
+	if originalTopic == "" {
+		originalTopic = m.Topic
+	}
+
+	delay := c.Tiers[attempt]
+	headers := setHeader(m.Headers, RetryAttemptHeader, strconv.Itoa(attempt+1))
+	headers = setHeader(headers, RetryOriginalTopicHeader, originalTopic)
+	headers = setHeader(headers, RetryNotBeforeHeader, time.Now().UTC().Add(delay).Format(time.RFC3339Nano))
+	headers = setHeader(headers, RetryReasonHeader, reason.Error())
+
+	return kafka.Message{
+		Topic:   RetryTopicName(originalTopic, delay),
+		Key:     m.Key,
+		Value:   m.Value,
+		Headers: headers,
+		Time:    time.Now().UTC(),
+	}, true
+}
+
+type retryRedeliverer struct {
+	log      logger.Logger
+	cfg      *Config
+	broker   Broker
+	topics   []string
+	producer Producer
+}
+
+// NewRetryRedeliverer consumes retry topics of the given topics and republishes messages to the original topic once due
+func NewRetryRedeliverer(log logger.Logger, cfg *Config, broker Broker, topics []string, producer Producer) *retryRedeliverer {
+	return &retryRedeliverer{log: log, cfg: cfg, broker: broker, topics: topics, producer: producer}
+}
+
+// Run starts one consumer per tier until ctx is done, messages of a tier share the delay so they become due in order
+func (r *retryRedeliverer) Run(ctx context.Context) {
+	groupID := fmt.Sprintf("%s_retry", r.cfg.GroupID)
+
+	wg := &sync.WaitGroup{}
+	for _, delay := range r.cfg.Retry.Tiers {
+		retryTopics := r.cfg.Retry.RetryTopics(r.topics, delay)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reader := newInstrumentedReader(r.broker.NewReader(retryTopics, groupID), groupID)
+			go reader.runStats(ctx)
+			r.consumeTier(ctx, reader)
+		}()
+	}
+	wg.Wait()
+}
+
+// consumeTier republishes due messages in fetch order. A message is only committed once it is republished,
+// publish failures are retried with backoff so later offsets of the partition are never committed past it.
+func (r *retryRedeliverer) consumeTier(ctx context.Context, reader Reader) {
+	defer func() {
+		if err := reader.Close(); err != nil {
+			r.log.Warnf("retryRedeliverer.reader.Close: %v", err)
+		}
+	}()
+
+	fetchBackoff := newBackoff()
+	for {
+		m, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.log.Warnf("retryRedeliverer.FetchMessage: %v, retry in: %v", err, fetchBackoff.Delay())
+			if !fetchBackoff.Wait(ctx) {
+				return
+			}
+			continue
+		}
+		fetchBackoff.Reset()
+
+		if notBefore, err := time.Parse(time.RFC3339Nano, getHeader(m.Headers, RetryNotBeforeHeader)); err == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Until(notBefore)):
+			}
+		}
+
+		if !r.redeliver(ctx, m) {
+			return
+		}
+
+		if err := reader.CommitMessages(ctx, m); err != nil {
+			r.log.WarnMsg("retryRedeliverer.CommitMessages", err)
+		}
+	}
+}
+
+// redeliver publishes the message to its original topic until it succeeds, returns false when ctx is done first
+func (r *retryRedeliverer) redeliver(ctx context.Context, m kafka.Message) bool {
+	originalTopic := getHeader(m.Headers, RetryOriginalTopicHeader)
+	if originalTopic == "" {
+		r.log.Warnf("retryRedeliverer message without original topic, topic: %s, offset: %d", m.Topic, m.Offset)
+		return true
+	}
+
+	publishBackoff := newBackoff()
+	for {
+		err := r.producer.PublishMessage(ctx, kafka.Message{
+			Topic:   originalTopic,
+			Key:     m.Key,
+			Value:   m.Value,
+			Headers: m.Headers,
+			Time:    time.Now().UTC(),
+		})
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		r.log.Warnf("retryRedeliverer.PublishMessage: %v, topic: %s, offset: %d, retry in: %v", err, m.Topic, m.Offset, publishBackoff.Delay())
+		if !publishBackoff.Wait(ctx) {
+			return false
+		}
+	}
+}
+
+func getHeader(headers []kafka.Header, key string) string {
+	for _, header := range headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func setHeader(headers []kafka.Header, key string, value string) []kafka.Header {
+	result := make([]kafka.Header, 0, len(headers)+1)
+	for _, header := range headers {
+		if header.Key != key {
+			result = append(result, header)
+		}
+	}
+	return append(result, kafka.Header{Key: key, Value: []byte(value)})
+}
@@ -0,0 +1,20 @@
+package kafka
+
+// TopicRoute event type of legacy messages published to the consumed topic without envelope and its dead letter topic
+type TopicRoute struct {
+	EventType       string
+	DeadLetterTopic string
+}
+
+// TopicRoutes consumed topic routes by topic name
+type TopicRoutes map[string]TopicRoute
+
+// EventType event type of legacy messages published without envelope
+func (r TopicRoutes) EventType(topic string) string {
+	return r[topic].EventType
+}
+
+// DeadLetterTopic returns dead letter topic name for the consumed topic
+func (r TopicRoutes) DeadLetterTopic(topic string) string {
+	return r[topic].DeadLetterTopic
+}
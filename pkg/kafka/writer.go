@@ -5,11 +5,11 @@ import (
 	"github.com/segmentio/kafka-go/compress"
 )
 
-// NewWriter create new configured kafka writer
+// NewWriter create new configured kafka writer, messages with the same key go to the same partition
 func NewWriter(brokers []string, errLogger kafka.Logger) *kafka.Writer {
 	w := &kafka.Writer{
 		Addr:         kafka.TCP(brokers...),
-		Balancer:     &kafka.LeastBytes{},
+		Balancer:     &kafka.Hash{},
 		RequiredAcks: writerRequiredAcks,
 		MaxAttempts:  writerMaxAttempts,
 		ErrorLogger:  errLogger,
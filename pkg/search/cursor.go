@@ -0,0 +1,66 @@
+package search
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultCursorSize page size of cursor mode when none is requested
+const DefaultCursorSize = 10
+
+// Cursor is the sort key and id of the last product of a page, clients get it as an opaque string.
+// A nil Value is a missing sort field, an empty Sort orders by id only.
+type Cursor struct {
+	Sort  string      `bson:"s,omitempty"`
+	Desc  bool        `bson:"d,omitempty"`
+	Value interface{} `bson:"v"`
+	ID    string      `bson:"id"`
+}
+
+// NewCursor cursor positioned after the product with the given sort value and id
+func NewCursor(sort Sort, value interface{}, id string) *Cursor {
+	return &Cursor{Sort: sort.Field, Desc: sort.Desc, Value: value, ID: id}
+}
+
+// Encode returns the opaque cursor string
+func (c *Cursor) Encode() (string, error) {
+	data, err := bson.Marshal(c)
+	if err != nil {
+		return "", errors.Wrap(err, "bson.Marshal")
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Matches reports whether the cursor was issued for the same sort
+func (c *Cursor) Matches(sort Sort) bool {
+	return c.Sort == sort.Field && c.Desc == sort.Desc
+}
+
+// DecodeCursor parses an opaque cursor string, errors wrap ErrInvalidQuery
+func DecodeCursor(cursor string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidQuery, "malformed cursor")
+	}
+
+	var c Cursor
+	if err := bson.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, errors.Wrap(ErrInvalidQuery, "malformed cursor")
+	}
+	if _, ok := sortFields[strings.ToLower(c.Sort)]; c.Sort != "" && !ok {
+		return nil, errors.Wrap(ErrInvalidQuery, "malformed cursor")
+	}
+
+	// only scalar sort keys, the value ends up in a filter and must not carry query operators
+	switch c.Value.(type) {
+	case nil, string, float64, primitive.DateTime:
+	default:
+		return nil, errors.Wrap(ErrInvalidQuery, "malformed cursor")
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2021, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor *Cursor
+		want   *Cursor
+	}{
+		{
+			name:   "id only",
+			cursor: NewCursor(Sort{}, nil, "p1"),
+			want:   &Cursor{ID: "p1"},
+		},
+		{
+			name:   "string value",
+			cursor: NewCursor(Sort{Field: SortName}, "boot", "p1"),
+			want:   &Cursor{Sort: SortName, Value: "boot", ID: "p1"},
+		},
+		{
+			name:   "descending number value",
+			cursor: NewCursor(Sort{Field: SortPrice, Desc: true}, 9.5, "p1"),
+			want:   &Cursor{Sort: SortPrice, Desc: true, Value: 9.5, ID: "p1"},
+		},
+		{
+			name:   "date value",
+			cursor: NewCursor(Sort{Field: SortCreatedAt}, createdAt, "p1"),
+			want:   &Cursor{Sort: SortCreatedAt, Value: primitive.NewDateTimeFromTime(createdAt), ID: "p1"},
+		},
+		{
+			name:   "nil value",
+			cursor: NewCursor(Sort{Field: SortPrice, Desc: true}, nil, "p1"),
+			want:   &Cursor{Sort: SortPrice, Desc: true, ID: "p1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.cursor.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			got, err := DecodeCursor(encoded)
+			if err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("DecodeCursor(Encode()) = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(doc bson.M) string {
+		data, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatalf("bson.Marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "%%%"},
+		{name: "not bson", cursor: base64.RawURLEncoding.EncodeToString([]byte("cursor"))},
+		{name: "missing id", cursor: encode(bson.M{"s": SortName, "v": "boot"})},
+		{name: "unknown sort", cursor: encode(bson.M{"s": "_id", "v": "boot", "id": "p1"})},
+		{name: "operator value", cursor: encode(bson.M{"s": SortName, "v": bson.M{"$ne": nil}, "id": "p1"})},
+		{name: "array value", cursor: encode(bson.M{"s": SortName, "v": bson.A{"boot"}, "id": "p1"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.cursor); !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("DecodeCursor error = %v, want %v", err, ErrInvalidQuery)
+			}
+		})
+	}
+}
+
+func TestCursorMatches(t *testing.T) {
+	cursor := NewCursor(Sort{Field: SortPrice, Desc: true}, 9.5, "p1")
+
+	if !cursor.Matches(Sort{Field: SortPrice, Desc: true}) {
+		t.Fatal("cursor does not match the sort it was issued for")
+	}
+	if cursor.Matches(Sort{Field: SortPrice}) {
+		t.Fatal("cursor matches the opposite direction")
+	}
+	if cursor.Matches(Sort{Field: SortName, Desc: true}) {
+		t.Fatal("cursor matches another sort field")
+	}
+}
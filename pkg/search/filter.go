@@ -0,0 +1,34 @@
+package search
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Filter narrows search results by price and dates, nil prices and zero dates are open bounds
+type Filter struct {
+	MinPrice    *float64
+	MaxPrice    *float64
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+}
+
+// Validate checks that the bounds are not negative or reversed, errors wrap ErrInvalidQuery
+func (f Filter) Validate() error {
+	if (f.MinPrice != nil && *f.MinPrice < 0) || (f.MaxPrice != nil && *f.MaxPrice < 0) {
+		return errors.Wrap(ErrInvalidQuery, "price filter can not be negative")
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return errors.Wrap(ErrInvalidQuery, "minPrice is greater than maxPrice")
+	}
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && f.CreatedFrom.After(f.CreatedTo) {
+		return errors.Wrap(ErrInvalidQuery, "createdFrom is after createdTo")
+	}
+	if !f.UpdatedFrom.IsZero() && !f.UpdatedTo.IsZero() && f.UpdatedFrom.After(f.UpdatedTo) {
+		return errors.Wrap(ErrInvalidQuery, "updatedFrom is after updatedTo")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package search
+
+import "strings"
+
+// Matches evaluates the query against a single product in memory, free text terms are case insensitive
+// substrings of the name or description, which approximates the text index used by Search
+func (q *Query) Matches(name, description string, price float64) bool {
+	name, description = strings.ToLower(name), strings.ToLower(description)
+
+	for _, term := range q.Terms {
+		value := strings.ToLower(term.Value)
+		found := strings.Contains(name, value) || strings.Contains(description, value)
+		if found == term.Negate {
+			return false
+		}
+	}
+
+	for _, field := range q.Fields {
+		text := name
+		if field.Field == FieldDescription {
+			text = description
+		}
+		if strings.Contains(text, strings.ToLower(field.Value)) == field.Negate {
+			return false
+		}
+	}
+
+	for _, priceRange := range q.Prices {
+		if priceRange.contains(price) == priceRange.Negate {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r PriceRange) contains(price float64) bool {
+	if r.Min != nil && (price < *r.Min || r.MinExclusive && price == *r.Min) {
+		return false
+	}
+	if r.Max != nil && (price > *r.Max || r.MaxExclusive && price == *r.Max) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,284 @@
+package search
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldPrice       = "price"
+
+	maxQueryLength = 256
+	maxClauses     = 20
+)
+
+// ErrInvalidQuery is returned for search strings that do not follow the query language
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Query is a parsed search string.
+//
+// Supported syntax, clauses separated by whitespace and combined with AND:
+//
+//	term                free text word matched through the text index
+//	"some phrase"       exact phrase matched through the text index
+//	name:value          case insensitive literal match on a field (name, description), value may be quoted
+//	price:10..50        inclusive price range, either bound may be omitted (price:10.., price:..50)
+//	price:>10           comparison on price (>, >=, <, <=) or exact price (price:10)
+//	-clause             negates any of the above
+type Query struct {
+	Terms  []Term
+	Fields []FieldMatch
+	Prices []PriceRange
+	Filter Filter
+	Sort   Sort
+}
+
+// Term is a free text word or phrase
+type Term struct {
+	Value  string
+	Phrase bool
+	Negate bool
+}
+
+// FieldMatch is a literal substring match on a single text field
+type FieldMatch struct {
+	Field  string
+	Value  string
+	Negate bool
+}
+
+// PriceRange bounds the price, nil bounds are open
+type PriceRange struct {
+	Min          *float64
+	Max          *float64
+	MinExclusive bool
+	MaxExclusive bool
+	Negate       bool
+}
+
+// Empty reports whether the query matches every product
+func (q *Query) Empty() bool {
+	return len(q.Terms) == 0 && len(q.Fields) == 0 && len(q.Prices) == 0
+}
+
+// HasText reports whether the query has at least one positive free text term, which is required to use the text index
+func (q *Query) HasText() bool {
+	for _, t := range q.Terms {
+		if !t.Negate {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the filter and that relevance sorting has text to rank by, errors wrap ErrInvalidQuery
+func (q *Query) Validate() error {
+	if err := q.Filter.Validate(); err != nil {
+		return err
+	}
+	if q.Sort.Field == SortRelevance && !q.HasText() {
+		return errors.Wrap(ErrInvalidQuery, "sorting by relevance requires search terms")
+	}
+	return nil
+}
+
+// Parse parses the search string, errors wrap ErrInvalidQuery
+func Parse(input string) (*Query, error) {
+	input = strings.TrimSpace(input)
+	if len(input) > maxQueryLength {
+		return nil, errors.Wrapf(ErrInvalidQuery, "query is longer than %d characters", maxQueryLength)
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) > maxClauses {
+		return nil, errors.Wrapf(ErrInvalidQuery, "query has more than %d clauses", maxClauses)
+	}
+
+	query := &Query{}
+	for _, tok := range tokens {
+		if err := query.add(tok); err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
+
+type token struct {
+	negate bool
+	field  string
+	value  string
+	quoted bool
+}
+
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		var tok token
+		if runes[i] == '-' {
+			tok.negate = true
+			i++
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '"' && runes[i] != ':' {
+			i++
+		}
+		if i < len(runes) && runes[i] == ':' {
+			tok.field = strings.ToLower(string(runes[start:i]))
+			if tok.field == "" {
+				return nil, errors.Wrapf(ErrInvalidQuery, "missing field name at position %d", start)
+			}
+			i++
+			start = i
+		} else {
+			i = start
+		}
+
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.Wrapf(ErrInvalidQuery, "unterminated quote at position %d", i)
+			}
+			tok.value = strings.TrimSpace(string(runes[i+1 : end]))
+			tok.quoted = true
+			i = end + 1
+			if i < len(runes) && !unicode.IsSpace(runes[i]) {
+				return nil, errors.Wrapf(ErrInvalidQuery, "unexpected character %q after quote", runes[i])
+			}
+		} else {
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				if runes[i] == '"' {
+					return nil, errors.Wrapf(ErrInvalidQuery, "unexpected quote at position %d", i)
+				}
+				i++
+			}
+			tok.value = string(runes[start:i])
+		}
+
+		if tok.value == "" {
+			return nil, errors.Wrapf(ErrInvalidQuery, "empty clause at position %d", start)
+		}
+		tokens = append(tokens, tok)
+	}
+
+	return tokens, nil
+}
+
+func (q *Query) add(tok token) error {
+	switch tok.field {
+	case "":
+		if strings.HasPrefix(tok.value, "-") {
+			return errors.Wrapf(ErrInvalidQuery, "unexpected '-' in %q", tok.value)
+		}
+		q.Terms = append(q.Terms, Term{Value: tok.value, Phrase: tok.quoted, Negate: tok.negate})
+	case FieldName, FieldDescription:
+		q.Fields = append(q.Fields, FieldMatch{Field: tok.field, Value: tok.value, Negate: tok.negate})
+	case FieldPrice:
+		if tok.quoted {
+			return errors.Wrap(ErrInvalidQuery, "price can not be quoted")
+		}
+		priceRange, err := parsePrice(tok.value)
+		if err != nil {
+			return err
+		}
+		priceRange.Negate = tok.negate
+		q.Prices = append(q.Prices, *priceRange)
+	default:
+		return errors.Wrapf(ErrInvalidQuery, "unknown field %q, expected one of name, description, price", tok.field)
+	}
+	return nil
+}
+
+func parsePrice(value string) (*PriceRange, error) {
+	switch {
+	case strings.HasPrefix(value, ">="):
+		min, err := parseAmount(value[2:])
+		return &PriceRange{Min: min}, err
+	case strings.HasPrefix(value, ">"):
+		min, err := parseAmount(value[1:])
+		return &PriceRange{Min: min, MinExclusive: true}, err
+	case strings.HasPrefix(value, "<="):
+		max, err := parseAmount(value[2:])
+		return &PriceRange{Max: max}, err
+	case strings.HasPrefix(value, "<"):
+		max, err := parseAmount(value[1:])
+		return &PriceRange{Max: max, MaxExclusive: true}, err
+	}
+
+	bounds := strings.SplitN(value, "..", 2)
+	if len(bounds) == 1 {
+		exact, err := parseAmount(value)
+		return &PriceRange{Min: exact, Max: exact}, err
+	}
+	if bounds[0] == "" && bounds[1] == "" {
+		return nil, errors.Wrapf(ErrInvalidQuery, "price range %q has no bounds", value)
+	}
+
+	priceRange := &PriceRange{}
+	if bounds[0] != "" {
+		min, err := parseAmount(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		priceRange.Min = min
+	}
+	if bounds[1] != "" {
+		max, err := parseAmount(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		priceRange.Max = max
+	}
+	if priceRange.Min != nil && priceRange.Max != nil && *priceRange.Min > *priceRange.Max {
+		return nil, errors.Wrapf(ErrInvalidQuery, "price range %q is reversed", value)
+	}
+
+	return priceRange, nil
+}
+
+func parseAmount(value string) (*float64, error) {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return nil, errors.Wrapf(ErrInvalidQuery, "invalid price %q", value)
+	}
+	return &amount, nil
+}
+
+// Key is a canonical form of the query for cache keys, equivalent queries differing in case or spacing share it
+func (q *Query) Key() string {
+	normalized := *q
+	normalized.Terms = make([]Term, len(q.Terms))
+	for i, t := range q.Terms {
+		t.Value = strings.ToLower(t.Value)
+		normalized.Terms[i] = t
+	}
+	normalized.Fields = make([]FieldMatch, len(q.Fields))
+	for i, f := range q.Fields {
+		f.Value = strings.ToLower(f.Value)
+		normalized.Fields[i] = f
+	}
+	normalized.Sort = q.ResolvedSort()
+
+	key, _ := json.Marshal(normalized) // nolint: errcheck
+	return string(key)
+}
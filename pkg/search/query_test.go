@@ -0,0 +1,192 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func amount(v float64) *float64 {
+	return &v
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *Query
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+			want:  &Query{},
+		},
+		{
+			name:  "terms",
+			input: "red  shoes",
+			want:  &Query{Terms: []Term{{Value: "red"}, {Value: "shoes"}}},
+		},
+		{
+			name:  "negated term",
+			input: "shoes -red",
+			want:  &Query{Terms: []Term{{Value: "shoes"}, {Value: "red", Negate: true}}},
+		},
+		{
+			name:  "phrase",
+			input: `"running shoes" -"high heels"`,
+			want: &Query{Terms: []Term{
+				{Value: "running shoes", Phrase: true},
+				{Value: "high heels", Phrase: true, Negate: true},
+			}},
+		},
+		{
+			name:  "fields",
+			input: `Name:boot -description:"faux leather"`,
+			want: &Query{Fields: []FieldMatch{
+				{Field: FieldName, Value: "boot"},
+				{Field: FieldDescription, Value: "faux leather", Negate: true},
+			}},
+		},
+		{
+			name:  "inclusive price range",
+			input: "price:10..50",
+			want:  &Query{Prices: []PriceRange{{Min: amount(10), Max: amount(50)}}},
+		},
+		{
+			name:  "open price ranges",
+			input: "price:10.. price:..50",
+			want:  &Query{Prices: []PriceRange{{Min: amount(10)}, {Max: amount(50)}}},
+		},
+		{
+			name:  "exclusive price bounds",
+			input: "price:>10 price:<50",
+			want: &Query{Prices: []PriceRange{
+				{Min: amount(10), MinExclusive: true},
+				{Max: amount(50), MaxExclusive: true},
+			}},
+		},
+		{
+			name:  "inclusive price comparisons",
+			input: "price:>=10 price:<=50",
+			want:  &Query{Prices: []PriceRange{{Min: amount(10)}, {Max: amount(50)}}},
+		},
+		{
+			name:  "exact and negated price",
+			input: "price:0 -price:>100",
+			want: &Query{Prices: []PriceRange{
+				{Min: amount(0), Max: amount(0)},
+				{Min: amount(100), MinExclusive: true, Negate: true},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "unterminated quote", input: `"running shoes`},
+		{name: "character after quote", input: `"running"shoes`},
+		{name: "quote inside term", input: `run"ning`},
+		{name: "missing field name", input: ":shoes"},
+		{name: "empty clause", input: "name:"},
+		{name: "double negation", input: "--shoes"},
+		{name: "unknown field", input: "color:red"},
+		{name: "quoted price", input: `price:"10"`},
+		{name: "negative price", input: "price:-10"},
+		{name: "invalid price", input: "price:ten"},
+		{name: "infinite price", input: "price:>Inf"},
+		{name: "price range without bounds", input: "price:.."},
+		{name: "reversed price range", input: "price:50..10"},
+		{name: "too long", input: strings.Repeat("a", maxQueryLength+1)},
+		{name: "too many clauses", input: strings.Repeat("a ", maxClauses+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.input); !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, ErrInvalidQuery)
+			}
+		})
+	}
+}
+
+func TestQueryKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		sameQuery bool
+	}{
+		{name: "case and spacing", a: `Red  name:Boot "Running Shoes"`, b: `red name:boot "running shoes"`, sameQuery: true},
+		{name: "equivalent price bounds", a: "price:>=10", b: "price:10..", sameQuery: true},
+		{name: "negation", a: "red", b: "-red"},
+		{name: "phrase", a: `"red"`, b: "red"},
+		{name: "exclusive bound", a: "price:>10", b: "price:>=10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(tt.a)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.a, err)
+			}
+			b, err := Parse(tt.b)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.b, err)
+			}
+			if same := a.Key() == b.Key(); same != tt.sameQuery {
+				t.Fatalf("Key(%q) == Key(%q) is %v, want %v", tt.a, tt.b, same, tt.sameQuery)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    Sort
+		invalid bool
+	}{
+		{orderBy: "", want: Sort{}},
+		{orderBy: "price", want: Sort{Field: SortPrice}},
+		{orderBy: "+price", want: Sort{Field: SortPrice}},
+		{orderBy: "-price", want: Sort{Field: SortPrice, Desc: true}},
+		{orderBy: "createdat:desc", want: Sort{Field: SortCreatedAt, Desc: true}},
+		{orderBy: "name:asc", want: Sort{Field: SortName}},
+		{orderBy: "name:up", invalid: true},
+		{orderBy: "_id", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.orderBy, func(t *testing.T) {
+			got, err := ParseSort(tt.orderBy)
+			if tt.invalid {
+				if !errors.Is(err, ErrInvalidQuery) {
+					t.Fatalf("ParseSort(%q) error = %v, want %v", tt.orderBy, err, ErrInvalidQuery)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort(%q): %v", tt.orderBy, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseSort(%q) = %+v, want %+v", tt.orderBy, got, tt.want)
+			}
+		})
+	}
+}
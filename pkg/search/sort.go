@@ -0,0 +1,78 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	SortName      = "name"
+	SortPrice     = "price"
+	SortCreatedAt = "createdAt"
+	SortUpdatedAt = "updatedAt"
+	SortRelevance = "relevance"
+)
+
+var sortFields = map[string]string{
+	strings.ToLower(SortName):      SortName,
+	strings.ToLower(SortPrice):     SortPrice,
+	strings.ToLower(SortCreatedAt): SortCreatedAt,
+	strings.ToLower(SortUpdatedAt): SortUpdatedAt,
+	strings.ToLower(SortRelevance): SortRelevance,
+}
+
+// Sort is a whitelisted sort field, the zero value keeps the default order
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// IsZero reports whether no sort was requested
+func (s Sort) IsZero() bool {
+	return s.Field == ""
+}
+
+// ParseSort parses orderBy values like "price" or "-price" (descending), also "price:desc" and "price:asc"
+func ParseSort(orderBy string) (Sort, error) {
+	orderBy = strings.TrimSpace(orderBy)
+	if orderBy == "" {
+		return Sort{}, nil
+	}
+
+	var sort Sort
+	field := orderBy
+	switch {
+	case strings.HasPrefix(field, "-"):
+		sort.Desc = true
+		field = field[1:]
+	case strings.HasPrefix(field, "+"):
+		field = field[1:]
+	}
+	if idx := strings.Index(field, ":"); idx != -1 {
+		switch strings.ToLower(field[idx+1:]) {
+		case "asc":
+		case "desc":
+			sort.Desc = true
+		default:
+			return Sort{}, errors.Wrapf(ErrInvalidQuery, "invalid sort direction in %q, expected asc or desc", orderBy)
+		}
+		field = field[:idx]
+	}
+
+	name, ok := sortFields[strings.ToLower(field)]
+	if !ok {
+		return Sort{}, errors.Wrapf(ErrInvalidQuery, "invalid sort field %q, expected one of name, price, createdAt, updatedAt, relevance", field)
+	}
+	sort.Field = name
+
+	return sort, nil
+}
+
+// ResolvedSort is the requested sort, text searches without one are ranked by relevance
+func (q *Query) ResolvedSort() Sort {
+	if q.Sort.IsZero() && q.HasText() {
+		return Sort{Field: SortRelevance, Desc: true}
+	}
+	return q.Sort
+}
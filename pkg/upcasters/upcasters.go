@@ -0,0 +1,27 @@
+package upcasters
+
+import (
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
+	"google.golang.org/protobuf/proto"
+)
+
+// Products transform old product event payloads into the current shape. A v1 ProductUpdated has no version
+// and none can be derived from the payload, it is applied as is and the reader orders it by updatedAt.
+var Products = kafkaClient.Upcasters{
+	kafkaMessages.ProductCreatedType: {1: upcastProductCreatedV1},
+}
+
+// upcastProductCreatedV1 v1 products had no version, a created product is at version 1
+func upcastProductCreatedV1(value []byte) ([]byte, error) {
+	msg := &kafkaMessages.ProductCreated{}
+	if err := proto.Unmarshal(value, msg); err != nil {
+		return nil, err
+	}
+
+	if msg.GetProduct() != nil && msg.GetProduct().GetVersion() == 0 {
+		msg.Product.Version = 1
+	}
+
+	return proto.Marshal(msg)
+}
@@ -0,0 +1,31 @@
+package kafkaMessages
+
+// Event types carried in the envelope event-type header
+const (
+	ProductCreateType  = "ProductCreate"
+	ProductUpdateType  = "ProductUpdate"
+	ProductDeleteType  = "ProductDelete"
+	ProductCreatedType = "ProductCreated"
+	ProductUpdatedType = "ProductUpdated"
+	ProductDeletedType = "ProductDeleted"
+
+	ProductCommandResultType = "ProductCommandResult"
+)
+
+// Current schema versions, v1 events were published before products had a version
+const (
+	ProductCreateSchemaVersion  = 1
+	ProductUpdateSchemaVersion  = 1
+	ProductDeleteSchemaVersion  = 1
+	ProductCreatedSchemaVersion = 2
+	ProductUpdatedSchemaVersion = 2
+	ProductDeletedSchemaVersion = 1
+
+	ProductCommandResultSchemaVersion = 1
+)
+
+// Command result statuses, a rejected command carries the reason
+const (
+	CommandAccepted = "accepted"
+	CommandRejected = "rejected"
+)
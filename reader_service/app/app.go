@@ -0,0 +1,12 @@
+package app
+
+import (
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/server"
+)
+
+// Run runs the service until the interrupt signal, lets the single process runner start it next to the other services
+func Run(log logger.Logger, cfg *config.Config) error {
+	return server.NewServer(log, cfg).Run()
+}
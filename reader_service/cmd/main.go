@@ -3,16 +3,26 @@ package main
 import (
 	"flag"
 	"log"
+	"time"
 
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/rebuild"
 	"github.com/herhu/Microservices-PR/reader_service/internal/server"
 )
 
+var (
+	configPath        = flag.String("config", "", "Reader microservice config path")
+	rebuildProjection = flag.Bool("rebuild", false, "Rebuild products projection and exit")
+	rebuildSource     = flag.String("rebuild-source", rebuild.SourceWriter, "Rebuild source: writer or kafka")
+	rebuildFrom       = flag.String("rebuild-from", "", "Replay product topics from RFC3339 time, empty replays from the first offset")
+	rebuildIdle       = flag.Duration("rebuild-idle", 15*time.Second, "Stop replaying product topics when idle for the duration")
+)
+
 func main() {
 	flag.Parse()
 
-	cfg, err := config.InitConfig()
+	cfg, err := config.InitConfig(*configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -22,5 +32,19 @@ func main() {
 	appLogger.WithName("ReaderService")
 
 	s := server.NewServer(appLogger, cfg)
+
+	if *rebuildProjection {
+		opts := rebuild.Options{Source: *rebuildSource, IdleTimeout: *rebuildIdle}
+		if *rebuildFrom != "" {
+			if opts.From, err = time.Parse(time.RFC3339, *rebuildFrom); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := s.RunRebuild(opts); err != nil {
+			appLogger.Fatal(err)
+		}
+		return
+	}
+
 	appLogger.Fatal(s.Run())
 }
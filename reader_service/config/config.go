@@ -1,9 +1,9 @@
 package config
 
 import (
-	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/herhu/Microservices-PR/pkg/constants"
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
@@ -13,16 +13,11 @@ import (
 	"github.com/herhu/Microservices-PR/pkg/probes"
 	"github.com/herhu/Microservices-PR/pkg/redis"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
-var configPath string
-
-func init() {
-	flag.StringVar(&configPath, "config", "", "Reader microservice config path")
-}
-
 type Config struct {
 	ServiceName      string              `mapstructure:"serviceName"`
 	Logger           *logger.Config      `mapstructure:"logger"`
@@ -39,8 +34,9 @@ type Config struct {
 }
 
 type GRPC struct {
-	Port        string `mapstructure:"port"`
-	Development bool   `mapstructure:"development"`
+	Port              string `mapstructure:"port"`
+	Development       bool   `mapstructure:"development"`
+	WriterServicePort string `mapstructure:"writerServicePort"`
 }
 
 type MongoCollections struct {
@@ -51,13 +47,35 @@ type KafkaTopics struct {
 	ProductCreated kafkaClient.TopicConfig `mapstructure:"productCreated"`
 	ProductUpdated kafkaClient.TopicConfig `mapstructure:"productUpdated"`
 	ProductDeleted kafkaClient.TopicConfig `mapstructure:"productDeleted"`
+
+	ProductCreatedDLQ kafkaClient.TopicConfig `mapstructure:"productCreatedDLQ"`
+	ProductUpdatedDLQ kafkaClient.TopicConfig `mapstructure:"productUpdatedDLQ"`
+	ProductDeletedDLQ kafkaClient.TopicConfig `mapstructure:"productDeletedDLQ"`
+}
+
+// Routes consumed topic routes for legacy messages and dead lettering
+func (t KafkaTopics) Routes() kafkaClient.TopicRoutes {
+	return kafkaClient.TopicRoutes{
+		t.ProductCreated.TopicName: {EventType: kafkaMessages.ProductCreatedType, DeadLetterTopic: t.ProductCreatedDLQ.TopicName},
+		t.ProductUpdated.TopicName: {EventType: kafkaMessages.ProductUpdatedType, DeadLetterTopic: t.ProductUpdatedDLQ.TopicName},
+		t.ProductDeleted.TopicName: {EventType: kafkaMessages.ProductDeletedType, DeadLetterTopic: t.ProductDeletedDLQ.TopicName},
+	}
 }
 
 type ServiceSettings struct {
-	RedisProductPrefixKey string `mapstructure:"redisProductPrefixKey"`
+	RedisProductPrefixKey string        `mapstructure:"redisProductPrefixKey"`
+	RedisProductTTL       time.Duration `mapstructure:"redisProductTTL"`
+	RedisProductTTLJitter time.Duration `mapstructure:"redisProductTTLJitter"`
+	RedisNotFoundTTL      time.Duration `mapstructure:"redisNotFoundTTL"`
+	RedisSearchPrefixKey  string        `mapstructure:"redisSearchPrefixKey"`
+	RedisSearchTTL        time.Duration `mapstructure:"redisSearchTTL"`
+	BatchGetMaxSize       int           `mapstructure:"batchGetMaxSize"`
+	RedisChangesStreamKey string        `mapstructure:"redisChangesStreamKey"`
+	RedisChangesMaxLen    int64         `mapstructure:"redisChangesMaxLen"`
 }
 
-func InitConfig() (*Config, error) {
+// InitConfig loads the config file, an empty path falls back to the config path env and the default config
+func InitConfig(configPath string) (*Config, error) {
 	if configPath == "" {
 		configPathFromEnv := os.Getenv(constants.ConfigPath)
 		if configPathFromEnv != "" {
@@ -117,6 +135,14 @@ func InitConfig() (*Config, error) {
 	if kafkaBrokers != "" {
 		cfg.Kafka.Brokers = []string{kafkaBrokers}
 	}
+	writerServicePort := os.Getenv(constants.WriterServicePort)
+	if writerServicePort != "" {
+		cfg.GRPC.WriterServicePort = writerServicePort
+	}
+	kafkaDriver := os.Getenv(constants.KafkaDriver)
+	if kafkaDriver != "" {
+		cfg.Kafka.Driver = kafkaDriver
+	}
 	jaegerAddr := os.Getenv(constants.JaegerHostPort)
 	if jaegerAddr != "" {
 		cfg.Jaeger.HostPort = jaegerAddr
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/pkg/interceptors"
+	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+func NewWriterServiceConn(ctx context.Context, cfg *config.Config, im interceptors.InterceptorManager) (*grpc.ClientConn, error) {
+	writerServiceConn, err := grpc.DialContext(
+		ctx,
+		cfg.GRPC.WriterServicePort,
+		grpc.WithUnaryInterceptor(im.ClientRequestLoggerInterceptor()),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "grpc.DialContext")
+	}
+
+	return writerServiceConn, nil
+}
@@ -12,18 +12,31 @@ type ReaderServiceMetrics struct {
 	SuccessGrpcRequests prometheus.Counter
 	ErrorGrpcRequests   prometheus.Counter
 
-	CreateProductGrpcRequests  prometheus.Counter
-	UpdateProductGrpcRequests  prometheus.Counter
-	DeleteProductGrpcRequests  prometheus.Counter
-	GetProductByIdGrpcRequests prometheus.Counter
-	SearchProductGrpcRequests  prometheus.Counter
+	CreateProductGrpcRequests    prometheus.Counter
+	UpdateProductGrpcRequests    prometheus.Counter
+	DeleteProductGrpcRequests    prometheus.Counter
+	GetProductByIdGrpcRequests   prometheus.Counter
+	GetProductsByIdsGrpcRequests prometheus.Counter
+	SearchProductGrpcRequests    prometheus.Counter
+	WatchProductsGrpcRequests    prometheus.Counter
+	ActiveProductWatchers        prometheus.Gauge
 
-	SuccessKafkaMessages prometheus.Counter
-	ErrorKafkaMessages   prometheus.Counter
+	SuccessKafkaMessages   prometheus.Counter
+	ErrorKafkaMessages     prometheus.Counter
+	RetryKafkaMessages     prometheus.Counter
+	DlqFailedKafkaMessages prometheus.Counter
+	StaleKafkaMessages     prometheus.Counter
 
 	CreateProductKafkaMessages prometheus.Counter
 	UpdateProductKafkaMessages prometheus.Counter
 	DeleteProductKafkaMessages prometheus.Counter
+
+	CacheHits         prometheus.Counter
+	CacheMisses       prometheus.Counter
+	CacheNegativeHits prometheus.Counter
+	SearchCacheHits   prometheus.Counter
+	SearchCacheMisses prometheus.Counter
+	CacheDuration     *prometheus.HistogramVec
 }
 
 func NewReaderServiceMetrics(cfg *config.Config) *ReaderServiceMetrics {
@@ -52,10 +65,22 @@ func NewReaderServiceMetrics(cfg *config.Config) *ReaderServiceMetrics {
 			Name: fmt.Sprintf("%s_get_product_by_id_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of get product by id grpc requests",
 		}),
+		GetProductsByIdsGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_products_by_ids_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of get products by ids grpc requests",
+		}),
 		SearchProductGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_search_product_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of search product grpc requests",
 		}),
+		WatchProductsGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_watch_products_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of watch products grpc streams opened",
+		}),
+		ActiveProductWatchers: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("%s_active_product_watchers", cfg.ServiceName),
+			Help: "The number of open watch products grpc streams",
+		}),
 		CreateProductKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_create_product_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of create product kafka messages",
@@ -76,5 +101,42 @@ func NewReaderServiceMetrics(cfg *config.Config) *ReaderServiceMetrics {
 			Name: fmt.Sprintf("%s_error_kafka_processed_messages_total", cfg.ServiceName),
 			Help: "The total number of error kafka processed messages",
 		}),
+		RetryKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_retry_kafka_processed_messages_total", cfg.ServiceName),
+			Help: "The total number of kafka messages sent to delayed retry topics",
+		}),
+		DlqFailedKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_dlq_publish_failed_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of failed dead letter publish attempts, the publish is retried until it succeeds",
+		}),
+		StaleKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_stale_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of skipped stale or out of order kafka messages",
+		}),
+		CacheHits: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_cache_hits_total", cfg.ServiceName),
+			Help: "The total number of products served from the redis cache",
+		}),
+		CacheMisses: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_cache_misses_total", cfg.ServiceName),
+			Help: "The total number of product lookups not found in the redis cache",
+		}),
+		CacheNegativeHits: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_cache_negative_hits_total", cfg.ServiceName),
+			Help: "The total number of lookups answered by a cached not found entry",
+		}),
+		SearchCacheHits: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_search_cache_hits_total", cfg.ServiceName),
+			Help: "The total number of search pages served from the redis cache",
+		}),
+		SearchCacheMisses: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_search_cache_misses_total", cfg.ServiceName),
+			Help: "The total number of search pages not found in the redis cache",
+		}),
+		CacheDuration: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    fmt.Sprintf("%s_cache_duration_seconds", cfg.ServiceName),
+			Help:    "The duration of redis cache operations",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 12),
+		}, []string{"operation"}),
 	}
 }
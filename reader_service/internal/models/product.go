@@ -15,6 +15,8 @@ type Product struct {
 	Price       float64   `json:"price,omitempty" bson:"price,omitempty" validate:"required"`
 	CreatedAt   time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
 	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	Version     int64     `json:"version,omitempty" bson:"version,omitempty"`
+	Deleted     bool      `json:"-" bson:"deleted,omitempty"`
 }
 
 // ProductsList products list response with pagination
@@ -25,6 +27,13 @@ type ProductsList struct {
 	Size       int64      `json:"size" bson:"size"`
 	HasMore    bool       `json:"hasMore" bson:"hasMore"`
 	Products   []*Product `json:"products" bson:"products"`
+	NextCursor string     `json:"nextCursor,omitempty" bson:"nextCursor,omitempty"`
+}
+
+// ProductsBatch products found by id and the ids that do not exist
+type ProductsBatch struct {
+	Products    []*Product `json:"products"`
+	NotFoundIDs []string   `json:"notFoundIds"`
 }
 
 func NewProductListWithPagination(products []*Product, count int64, pagination *utils.Pagination) *ProductsList {
@@ -38,6 +47,16 @@ func NewProductListWithPagination(products []*Product, count int64, pagination *
 	}
 }
 
+// NewProductListWithCursor cursor mode page, totals are not counted
+func NewProductListWithCursor(products []*Product, size int, hasMore bool, nextCursor string) *ProductsList {
+	return &ProductsList{
+		Size:       int64(size),
+		HasMore:    hasMore,
+		Products:   products,
+		NextCursor: nextCursor,
+	}
+}
+
 func ProductToGrpcMessage(product *Product) *readerService.Product {
 	return &readerService.Product{
 		ProductID:   product.ProductID,
@@ -46,6 +65,7 @@ func ProductToGrpcMessage(product *Product) *readerService.Product {
 		Price:       product.Price,
 		CreatedAt:   timestamppb.New(product.CreatedAt),
 		UpdatedAt:   timestamppb.New(product.UpdatedAt),
+		Version:     product.Version,
 	}
 }
 
@@ -62,5 +82,15 @@ func ProductListToGrpc(products *ProductsList) *readerService.SearchRes {
 		Size:       products.Size,
 		HasMore:    products.HasMore,
 		Products:   list,
+		NextCursor: products.NextCursor,
+	}
+}
+
+func ProductsBatchToGrpc(batch *ProductsBatch) *readerService.GetProductsByIdsRes {
+	list := make([]*readerService.Product, 0, len(batch.Products))
+	for _, product := range batch.Products {
+		list = append(list, ProductToGrpcMessage(product))
 	}
+
+	return &readerService.GetProductsByIdsRes{Products: list, NotFoundIDs: batch.NotFoundIDs}
 }
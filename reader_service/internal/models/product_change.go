@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	ProductChangeCreated = "created"
+	ProductChangeUpdated = "updated"
+	ProductChangeDeleted = "deleted"
+)
+
+// ProductChange is a change applied to the projection, Product is nil for deletes.
+// ResumeToken is the position of the change in the change feed, CorrelationID the id of the request that caused it.
+type ProductChange struct {
+	Type          string    `json:"type"`
+	ProductID     string    `json:"productId"`
+	Version       int64     `json:"version"`
+	Product       *Product  `json:"product,omitempty"`
+	OccurredAt    time.Time `json:"occurredAt"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	ResumeToken   string    `json:"-"`
+}
+
+func ProductChangeToGrpc(change *ProductChange) *readerService.ProductChange {
+	res := &readerService.ProductChange{
+		Type:          change.Type,
+		ProductID:     change.ProductID,
+		Version:       change.Version,
+		OccurredAt:    timestamppb.New(change.OccurredAt),
+		ResumeToken:   change.ResumeToken,
+		CorrelationID: change.CorrelationID,
+	}
+	if change.Product != nil {
+		res.Product = ProductToGrpcMessage(change.Product)
+	}
+	return res
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	"github.com/pkg/errors"
+)
+
+// republishApplied handles a stale event whose version is the stored one, the event is redelivered because
+// publishing its change failed after the projection was written, so the stored change is published again.
+// When the first publish did succeed watchers receive the change twice with the same version.
+// Returns ErrStaleEvent once nothing is left to publish.
+func republishApplied(ctx context.Context, mongoRepo repository.Repository, changeFeed repository.ChangeFeed, changeType, productID string, version int64) error {
+	if version <= 0 {
+		return repository.ErrStaleEvent
+	}
+
+	stored, err := mongoRepo.GetStoredProduct(ctx, productID)
+	if err != nil {
+		return errors.Wrap(err, "GetStoredProduct")
+	}
+	if stored.Version != version || stored.Deleted != (changeType == models.ProductChangeDeleted) {
+		return repository.ErrStaleEvent
+	}
+
+	change := &models.ProductChange{
+		Type:       changeType,
+		ProductID:  productID,
+		Version:    version,
+		OccurredAt: stored.UpdatedAt,
+	}
+	if !stored.Deleted {
+		change.Product = stored
+	}
+	if err := changeFeed.Publish(ctx, change); err != nil {
+		return errors.Wrap(err, "changeFeed.Publish")
+	}
+	return repository.ErrStaleEvent
+}
@@ -26,10 +26,11 @@ type CreateProductCommand struct {
 	Price       float64   `json:"price,omitempty" bson:"price,omitempty" validate:"required"`
 	CreatedAt   time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
 	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	Version     int64     `json:"version,omitempty" bson:"version,omitempty"`
 }
 
-func NewCreateProductCommand(productID string, name string, description string, price float64, createdAt time.Time, updatedAt time.Time) *CreateProductCommand {
-	return &CreateProductCommand{ProductID: productID, Name: name, Description: description, Price: price, CreatedAt: createdAt, UpdatedAt: updatedAt}
+func NewCreateProductCommand(productID string, name string, description string, price float64, createdAt time.Time, updatedAt time.Time, version int64) *CreateProductCommand {
+	return &CreateProductCommand{ProductID: productID, Name: name, Description: description, Price: price, CreatedAt: createdAt, UpdatedAt: updatedAt, Version: version}
 }
 
 type UpdateProductCommand struct {
@@ -38,16 +39,18 @@ type UpdateProductCommand struct {
 	Description string    `json:"description,omitempty" bson:"description,omitempty" validate:"required,min=3,max=500"`
 	Price       float64   `json:"price,omitempty" bson:"price,omitempty" validate:"required"`
 	UpdatedAt   time.Time `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	Version     int64     `json:"version,omitempty" bson:"version,omitempty"`
 }
 
-func NewUpdateProductCommand(productID string, name string, description string, price float64, updatedAt time.Time) *UpdateProductCommand {
-	return &UpdateProductCommand{ProductID: productID, Name: name, Description: description, Price: price, UpdatedAt: updatedAt}
+func NewUpdateProductCommand(productID string, name string, description string, price float64, updatedAt time.Time, version int64) *UpdateProductCommand {
+	return &UpdateProductCommand{ProductID: productID, Name: name, Description: description, Price: price, UpdatedAt: updatedAt, Version: version}
 }
 
 type DeleteProductCommand struct {
 	ProductID uuid.UUID `json:"productId" bson:"_id,omitempty"`
+	Version   int64     `json:"version,omitempty" bson:"version,omitempty"`
 }
 
-func NewDeleteProductCommand(productID uuid.UUID) *DeleteProductCommand {
-	return &DeleteProductCommand{ProductID: productID}
+func NewDeleteProductCommand(productID uuid.UUID, version int64) *DeleteProductCommand {
+	return &DeleteProductCommand{ProductID: productID, Version: version}
 }
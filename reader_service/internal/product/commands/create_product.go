@@ -8,6 +8,7 @@ import (
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 )
 
 type CreateProductCmdHandler interface {
@@ -15,14 +16,15 @@ type CreateProductCmdHandler interface {
 }
 
 type createProductHandler struct {
-	log       logger.Logger
-	cfg       *config.Config
-	mongoRepo repository.Repository
-	redisRepo repository.CacheRepository
+	log        logger.Logger
+	cfg        *config.Config
+	mongoRepo  repository.Repository
+	redisRepo  repository.CacheRepository
+	changeFeed repository.ChangeFeed
 }
 
-func NewCreateProductHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository) *createProductHandler {
-	return &createProductHandler{log: log, cfg: cfg, mongoRepo: mongoRepo, redisRepo: redisRepo}
+func NewCreateProductHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository, changeFeed repository.ChangeFeed) *createProductHandler {
+	return &createProductHandler{log: log, cfg: cfg, mongoRepo: mongoRepo, redisRepo: redisRepo, changeFeed: changeFeed}
 }
 
 func (c *createProductHandler) Handle(ctx context.Context, command *CreateProductCommand) error {
@@ -36,13 +38,27 @@ func (c *createProductHandler) Handle(ctx context.Context, command *CreateProduc
 		Price:       command.Price,
 		CreatedAt:   command.CreatedAt,
 		UpdatedAt:   command.UpdatedAt,
+		Version:     command.Version,
 	}
 
 	created, err := c.mongoRepo.CreateProduct(ctx, product)
 	if err != nil {
+		if errors.Is(err, repository.ErrStaleEvent) {
+			return republishApplied(ctx, c.mongoRepo, c.changeFeed, models.ProductChangeCreated, command.ProductID, command.Version)
+		}
 		return err
 	}
 
 	c.redisRepo.PutProduct(ctx, created.ProductID, created)
+	c.redisRepo.InvalidateSearches(ctx)
+	if err := c.changeFeed.Publish(ctx, &models.ProductChange{
+		Type:       models.ProductChangeCreated,
+		ProductID:  created.ProductID,
+		Version:    created.Version,
+		Product:    created,
+		OccurredAt: created.UpdatedAt,
+	}); err != nil {
+		return errors.Wrap(err, "changeFeed.Publish")
+	}
 	return nil
 }
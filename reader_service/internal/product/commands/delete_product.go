@@ -2,11 +2,14 @@ package commands
 
 import (
 	"context"
+	"time"
 
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 )
 
 type DeleteProductCmdHandler interface {
@@ -14,24 +17,37 @@ type DeleteProductCmdHandler interface {
 }
 
 type deleteProductCmdHandler struct {
-	log       logger.Logger
-	cfg       *config.Config
-	mongoRepo repository.Repository
-	redisRepo repository.CacheRepository
+	log        logger.Logger
+	cfg        *config.Config
+	mongoRepo  repository.Repository
+	redisRepo  repository.CacheRepository
+	changeFeed repository.ChangeFeed
 }
 
-func NewDeleteProductCmdHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository) *deleteProductCmdHandler {
-	return &deleteProductCmdHandler{log: log, cfg: cfg, mongoRepo: mongoRepo, redisRepo: redisRepo}
+func NewDeleteProductCmdHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository, changeFeed repository.ChangeFeed) *deleteProductCmdHandler {
+	return &deleteProductCmdHandler{log: log, cfg: cfg, mongoRepo: mongoRepo, redisRepo: redisRepo, changeFeed: changeFeed}
 }
 
 func (c *deleteProductCmdHandler) Handle(ctx context.Context, command *DeleteProductCommand) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteProductCmdHandler.Handle")
 	defer span.Finish()
 
-	if err := c.mongoRepo.DeleteProduct(ctx, command.ProductID); err != nil {
+	if err := c.mongoRepo.DeleteProduct(ctx, command.ProductID, command.Version); err != nil {
+		if errors.Is(err, repository.ErrStaleEvent) {
+			return republishApplied(ctx, c.mongoRepo, c.changeFeed, models.ProductChangeDeleted, command.ProductID.String(), command.Version)
+		}
 		return err
 	}
 
-	c.redisRepo.DelProduct(ctx, command.ProductID.String())
+	c.redisRepo.PutDeleted(ctx, command.ProductID.String(), command.Version)
+	c.redisRepo.InvalidateSearches(ctx)
+	if err := c.changeFeed.Publish(ctx, &models.ProductChange{
+		Type:       models.ProductChangeDeleted,
+		ProductID:  command.ProductID.String(),
+		Version:    command.Version,
+		OccurredAt: time.Now().UTC(),
+	}); err != nil {
+		return errors.Wrap(err, "changeFeed.Publish")
+	}
 	return nil
 }
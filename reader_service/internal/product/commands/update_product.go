@@ -8,6 +8,7 @@ import (
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 )
 
 type UpdateProductCmdHandler interface {
@@ -15,14 +16,15 @@ type UpdateProductCmdHandler interface {
 }
 
 type updateProductCmdHandler struct {
-	log       logger.Logger
-	cfg       *config.Config
-	mongoRepo repository.Repository
-	redisRepo repository.CacheRepository
+	log        logger.Logger
+	cfg        *config.Config
+	mongoRepo  repository.Repository
+	redisRepo  repository.CacheRepository
+	changeFeed repository.ChangeFeed
 }
 
-func NewUpdateProductCmdHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository) *updateProductCmdHandler {
-	return &updateProductCmdHandler{log: log, cfg: cfg, mongoRepo: mongoRepo, redisRepo: redisRepo}
+func NewUpdateProductCmdHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository, changeFeed repository.ChangeFeed) *updateProductCmdHandler {
+	return &updateProductCmdHandler{log: log, cfg: cfg, mongoRepo: mongoRepo, redisRepo: redisRepo, changeFeed: changeFeed}
 }
 
 func (c *updateProductCmdHandler) Handle(ctx context.Context, command *UpdateProductCommand) error {
@@ -35,13 +37,27 @@ func (c *updateProductCmdHandler) Handle(ctx context.Context, command *UpdatePro
 		Description: command.Description,
 		Price:       command.Price,
 		UpdatedAt:   command.UpdatedAt,
+		Version:     command.Version,
 	}
 
 	updated, err := c.mongoRepo.UpdateProduct(ctx, product)
 	if err != nil {
+		if errors.Is(err, repository.ErrStaleEvent) {
+			return republishApplied(ctx, c.mongoRepo, c.changeFeed, models.ProductChangeUpdated, command.ProductID, command.Version)
+		}
 		return err
 	}
 
 	c.redisRepo.PutProduct(ctx, updated.ProductID, updated)
+	c.redisRepo.InvalidateSearches(ctx)
+	if err := c.changeFeed.Publish(ctx, &models.ProductChange{
+		Type:       models.ProductChangeUpdated,
+		ProductID:  updated.ProductID,
+		Version:    updated.Version,
+		Product:    updated,
+		OccurredAt: updated.UpdatedAt,
+	}); err != nil {
+		return errors.Wrap(err, "changeFeed.Publish")
+	}
 	return nil
 }
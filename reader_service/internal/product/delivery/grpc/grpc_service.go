@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/go-playground/validator"
+	"github.com/herhu/Microservices-PR/pkg/constants"
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/search"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	"github.com/herhu/Microservices-PR/pkg/utils"
 	"github.com/herhu/Microservices-PR/reader_service/config"
@@ -13,13 +15,19 @@ import (
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/commands"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/queries"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/service"
 	readerService "github.com/herhu/Microservices-PR/reader_service/proto/product_reader"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const defaultBatchGetMaxSize = 100
+
 type grpcService struct {
 	log     logger.Logger
 	cfg     *config.Config
@@ -38,7 +46,7 @@ func (s *grpcService) CreateProduct(ctx context.Context, req *readerService.Crea
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.CreateProduct")
 	defer span.Finish()
 
-	command := commands.NewCreateProductCommand(req.GetProductID(), req.GetName(), req.GetDescription(), req.GetPrice(), time.Now(), time.Now())
+	command := commands.NewCreateProductCommand(req.GetProductID(), req.GetName(), req.GetDescription(), req.GetPrice(), time.Now(), time.Now(), 1)
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
@@ -46,7 +54,7 @@ func (s *grpcService) CreateProduct(ctx context.Context, req *readerService.Crea
 
 	if err := s.ps.Commands.CreateProduct.Handle(ctx, command); err != nil {
 		s.log.WarnMsg("CreateProduct.Handle", err)
-		return nil, s.errResponse(codes.InvalidArgument, err)
+		return nil, s.errResponse(commandErrCode(err), err)
 	}
 
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -59,7 +67,7 @@ func (s *grpcService) UpdateProduct(ctx context.Context, req *readerService.Upda
 	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.UpdateProduct")
 	defer span.Finish()
 
-	command := commands.NewUpdateProductCommand(req.GetProductID(), req.GetName(), req.GetDescription(), req.GetPrice(), time.Now())
+	command := commands.NewUpdateProductCommand(req.GetProductID(), req.GetName(), req.GetDescription(), req.GetPrice(), time.Now(), 0)
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
@@ -67,7 +75,7 @@ func (s *grpcService) UpdateProduct(ctx context.Context, req *readerService.Upda
 
 	if err := s.ps.Commands.UpdateProduct.Handle(ctx, command); err != nil {
 		s.log.WarnMsg("UpdateProduct.Handle", err)
-		return nil, s.errResponse(codes.InvalidArgument, err)
+		return nil, s.errResponse(commandErrCode(err), err)
 	}
 
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -102,6 +110,44 @@ func (s *grpcService) GetProductById(ctx context.Context, req *readerService.Get
 	return &readerService.GetProductByIdRes{Product: models.ProductToGrpcMessage(product)}, nil
 }
 
+func (s *grpcService) GetProductsByIds(ctx context.Context, req *readerService.GetProductsByIdsReq) (*readerService.GetProductsByIdsRes, error) {
+	s.metrics.GetProductsByIdsGrpcRequests.Inc()
+
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.GetProductsByIds")
+	defer span.Finish()
+
+	if maxSize := s.batchGetMaxSize(); len(req.GetProductIDs()) > maxSize {
+		err := errors.Errorf("at most %d product ids can be requested at once", maxSize)
+		s.log.WarnMsg("GetProductsByIds", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+
+	productUUIDs := make([]uuid.UUID, 0, len(req.GetProductIDs()))
+	for _, productID := range req.GetProductIDs() {
+		productUUID, err := uuid.FromString(productID)
+		if err != nil {
+			s.log.WarnMsg("uuid.FromString", err)
+			return nil, s.errResponse(codes.InvalidArgument, err)
+		}
+		productUUIDs = append(productUUIDs, productUUID)
+	}
+
+	query := queries.NewGetProductsByIdsQuery(productUUIDs)
+	if err := s.v.StructCtx(ctx, query); err != nil {
+		s.log.WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+
+	batch, err := s.ps.Queries.GetProductsByIds.Handle(ctx, query)
+	if err != nil {
+		s.log.WarnMsg("GetProductsByIds.Handle", err)
+		return nil, s.errResponse(codes.Internal, err)
+	}
+
+	s.metrics.SuccessGrpcRequests.Inc()
+	return models.ProductsBatchToGrpc(batch), nil
+}
+
 func (s *grpcService) SearchProduct(ctx context.Context, req *readerService.SearchReq) (*readerService.SearchRes, error) {
 	s.metrics.SearchProductGrpcRequests.Inc()
 
@@ -109,12 +155,16 @@ func (s *grpcService) SearchProduct(ctx context.Context, req *readerService.Sear
 	defer span.Finish()
 
 	pq := utils.NewPaginationQuery(int(req.GetSize()), int(req.GetPage()))
+	pq.SetOrderBy(req.GetOrderBy())
 
-	query := queries.NewSearchProductQuery(req.GetSearch(), pq)
+	query := queries.NewSearchProductQuery(req.GetSearch(), pq, searchFilterFromRequest(req))
+	if req.GetCursorMode() {
+		query.WithCursor(req.GetCursor())
+	}
 	productsList, err := s.ps.Queries.SearchProduct.Handle(ctx, query)
 	if err != nil {
 		s.log.WarnMsg("SearchProduct.Handle", err)
-		return nil, s.errResponse(codes.Internal, err)
+		return nil, s.errResponse(searchErrCode(err), err)
 	}
 
 	s.metrics.SuccessGrpcRequests.Inc()
@@ -133,7 +183,7 @@ func (s *grpcService) DeleteProductByID(ctx context.Context, req *readerService.
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 
-	if err := s.ps.Commands.DeleteProduct.Handle(ctx, commands.NewDeleteProductCommand(productUUID)); err != nil {
+	if err := s.ps.Commands.DeleteProduct.Handle(ctx, commands.NewDeleteProductCommand(productUUID, 0)); err != nil {
 		s.log.WarnMsg("DeleteProduct.Handle", err)
 		return nil, s.errResponse(codes.Internal, err)
 	}
@@ -142,6 +192,89 @@ func (s *grpcService) DeleteProductByID(ctx context.Context, req *readerService.
 	return &readerService.DeleteProductByIdRes{}, nil
 }
 
+// commandErrCode stale commands conflict with a newer stored product
+func commandErrCode(err error) codes.Code {
+	if errors.Is(err, repository.ErrStaleEvent) {
+		return codes.Aborted
+	}
+	return codes.InvalidArgument
+}
+
+func (s *grpcService) WatchProducts(req *readerService.WatchProductsReq, stream readerService.ReaderService_WatchProductsServer) error {
+	s.metrics.WatchProductsGrpcRequests.Inc()
+	s.metrics.ActiveProductWatchers.Inc()
+	defer s.metrics.ActiveProductWatchers.Dec()
+
+	ctx, span := tracing.StartGrpcServerTracerSpan(stream.Context(), "grpcService.WatchProducts")
+	defer span.Finish()
+
+	productUUIDs := make([]uuid.UUID, 0, len(req.GetProductIDs()))
+	for _, productID := range req.GetProductIDs() {
+		productUUID, err := uuid.FromString(productID)
+		if err != nil {
+			s.log.WarnMsg("uuid.FromString", err)
+			return s.errResponse(codes.InvalidArgument, err)
+		}
+		productUUIDs = append(productUUIDs, productUUID)
+	}
+
+	query := queries.NewWatchProductsQuery(productUUIDs, req.GetSearch(), req.GetResumeToken())
+	started := func(resumeToken string) error {
+		return stream.SendHeader(metadata.Pairs(constants.ResumeTokenMetadata, resumeToken))
+	}
+	err := s.ps.Queries.WatchProducts.Handle(ctx, query, started, func(change *models.ProductChange) error {
+		return stream.Send(models.ProductChangeToGrpc(change))
+	})
+	if err != nil {
+		s.log.WarnMsg("WatchProducts.Handle", err)
+		return s.errResponse(watchErrCode(err), err)
+	}
+
+	return nil
+}
+
+func watchErrCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, search.ErrInvalidQuery), errors.Is(err, repository.ErrInvalidResumeToken):
+		return codes.InvalidArgument
+	case errors.Is(err, repository.ErrResumeTokenExpired):
+		return codes.OutOfRange
+	}
+	return codes.Internal
+}
+
+func (s *grpcService) batchGetMaxSize() int {
+	if s.cfg.ServiceSettings.BatchGetMaxSize > 0 {
+		return s.cfg.ServiceSettings.BatchGetMaxSize
+	}
+	return defaultBatchGetMaxSize
+}
+
+func searchFilterFromRequest(req *readerService.SearchReq) search.Filter {
+	return search.Filter{
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		CreatedFrom: timeOrZero(req.GetCreatedFrom()),
+		CreatedTo:   timeOrZero(req.GetCreatedTo()),
+		UpdatedFrom: timeOrZero(req.GetUpdatedFrom()),
+		UpdatedTo:   timeOrZero(req.GetUpdatedTo()),
+	}
+}
+
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func searchErrCode(err error) codes.Code {
+	if errors.Is(err, search.ErrInvalidQuery) {
+		return codes.InvalidArgument
+	}
+	return codes.Internal
+}
+
 func (s *grpcService) errResponse(c codes.Code, err error) error {
 	s.metrics.ErrorGrpcRequests.Inc()
 	return status.Error(c, err.Error())
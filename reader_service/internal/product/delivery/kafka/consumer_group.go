@@ -2,13 +2,16 @@ package kafka
 
 import (
 	"context"
-	"sync"
 
 	"github.com/go-playground/validator"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/upcasters"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/reader_service/config"
 	"github.com/herhu/Microservices-PR/reader_service/internal/metrics"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/service"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -17,42 +20,64 @@ const (
 )
 
 type readerMessageProcessor struct {
-	log     logger.Logger
-	cfg     *config.Config
-	v       *validator.Validate
-	ps      *service.ProductService
-	metrics *metrics.ReaderServiceMetrics
+	log           logger.Logger
+	cfg           *config.Config
+	v             *validator.Validate
+	ps            *service.ProductService
+	metrics       *metrics.ReaderServiceMetrics
+	kafkaProducer kafkaClient.Producer
+	routes        kafkaClient.TopicRoutes
+	failures      kafkaClient.FailureHandler
 }
 
-func NewReaderMessageProcessor(log logger.Logger, cfg *config.Config, v *validator.Validate, ps *service.ProductService, metrics *metrics.ReaderServiceMetrics) *readerMessageProcessor {
-	return &readerMessageProcessor{log: log, cfg: cfg, v: v, ps: ps, metrics: metrics}
+func NewReaderMessageProcessor(
+	log logger.Logger,
+	cfg *config.Config,
+	v *validator.Validate,
+	ps *service.ProductService,
+	metrics *metrics.ReaderServiceMetrics,
+	kafkaProducer kafkaClient.Producer,
+) *readerMessageProcessor {
+	routes := cfg.KafkaTopics.Routes()
+	failures := kafkaClient.NewFailureHandler(log, cfg.Kafka, routes, kafkaProducer, kafkaClient.FailureMetrics{
+		Error:     metrics.ErrorKafkaMessages,
+		Retry:     metrics.RetryKafkaMessages,
+		DlqFailed: metrics.DlqFailedKafkaMessages,
+	}, nil)
+	return &readerMessageProcessor{log: log, cfg: cfg, v: v, ps: ps, metrics: metrics, kafkaProducer: kafkaProducer, routes: routes, failures: failures}
 }
 
-func (s *readerMessageProcessor) ProcessMessages(ctx context.Context, r *kafka.Reader, wg *sync.WaitGroup, workerID int) {
-	defer wg.Done()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-
-		m, err := r.FetchMessage(ctx)
-		if err != nil {
-			s.log.Warnf("workerID: %v, err: %v", workerID, err)
-			continue
-		}
-
-		s.logProcessMessage(m, workerID)
-
-		switch m.Topic {
-		case s.cfg.KafkaTopics.ProductCreated.TopicName:
-			s.processProductCreated(ctx, r, m)
-		case s.cfg.KafkaTopics.ProductUpdated.TopicName:
-			s.processProductUpdated(ctx, r, m)
-		case s.cfg.KafkaTopics.ProductDeleted.TopicName:
-			s.processProductDeleted(ctx, r, m)
-		}
+func (s *readerMessageProcessor) ProcessMessage(ctx context.Context, r kafkaClient.Reader, m kafka.Message, workerID int) {
+	s.logProcessMessage(m, workerID)
+
+	envelope, err := kafkaClient.MessageEnvelope(m, s.routes.EventType(m.Topic))
+	if err != nil {
+		s.log.WarnMsg("MessageEnvelope", err)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
+		return
+	}
+
+	m, err = upcasters.Products.UpcastMessage(m, envelope)
+	if err != nil {
+		s.log.WarnMsg("UpcastMessage", err)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
+		return
+	}
+
+	if envelope.CorrelationID != "" {
+		ctx = kafkaClient.ContextWithCorrelationID(ctx, envelope.CorrelationID)
+	}
+
+	switch envelope.EventType {
+	case kafkaMessages.ProductCreatedType:
+		s.processProductCreated(ctx, r, m)
+	case kafkaMessages.ProductUpdatedType:
+		s.processProductUpdated(ctx, r, m)
+	case kafkaMessages.ProductDeletedType:
+		s.processProductDeleted(ctx, r, m)
+	default:
+		err := errors.Errorf("unknown event type: %s", envelope.EventType)
+		s.log.WarnMsg("ProcessMessage", err)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
 	}
 }
@@ -2,26 +2,18 @@ package kafka
 
 import (
 	"context"
-	"time"
 
-	"github.com/avast/retry-go"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/commands"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 )
 
-const (
-	retryAttempts = 3
-	retryDelay    = 300 * time.Millisecond
-)
-
-var (
-	retryOptions = []retry.Option{retry.Attempts(retryAttempts), retry.Delay(retryDelay), retry.DelayType(retry.BackOffDelay)}
-)
-
-func (s *readerMessageProcessor) processProductCreated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *readerMessageProcessor) processProductCreated(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
 	s.metrics.CreateProductKafkaMessages.Inc()
 
 	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "readerMessageProcessor.processProductCreated")
@@ -30,23 +22,25 @@ func (s *readerMessageProcessor) processProductCreated(ctx context.Context, r *k
 	msg := &kafkaMessages.ProductCreated{}
 	if err := proto.Unmarshal(m.Value, msg); err != nil {
 		s.log.WarnMsg("proto.Unmarshal", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
 		return
 	}
 
 	p := msg.GetProduct()
-	command := commands.NewCreateProductCommand(p.GetProductID(), p.GetName(), p.GetDescription(), p.GetPrice(), p.GetCreatedAt().AsTime(), p.GetUpdatedAt().AsTime())
+	command := commands.NewCreateProductCommand(p.GetProductID(), p.GetName(), p.GetDescription(), p.GetPrice(), p.GetCreatedAt().AsTime(), p.GetUpdatedAt().AsTime(), p.GetVersion())
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
-	if err := retry.Do(func() error {
-		return s.ps.Commands.CreateProduct.Handle(ctx, command)
-	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+	if err := s.ps.Commands.CreateProduct.Handle(ctx, command); err != nil {
+		if errors.Is(err, repository.ErrStaleEvent) {
+			s.commitStaleMessage(ctx, r, m)
+			return
+		}
 		s.log.WarnMsg("CreateProduct.Handle", err)
-		s.metrics.ErrorKafkaMessages.Inc()
+		s.failures.RetryErrMessage(ctx, r, m, err)
 		return
 	}
 
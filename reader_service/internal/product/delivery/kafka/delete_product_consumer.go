@@ -3,16 +3,18 @@ package kafka
 import (
 	"context"
 
-	"github.com/avast/retry-go"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/commands"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 )
 
-func (s *readerMessageProcessor) processProductDeleted(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *readerMessageProcessor) processProductDeleted(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
 	s.metrics.DeleteProductKafkaMessages.Inc()
 
 	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "readerMessageProcessor.processProductDeleted")
@@ -21,29 +23,31 @@ func (s *readerMessageProcessor) processProductDeleted(ctx context.Context, r *k
 	msg := &kafkaMessages.ProductDeleted{}
 	if err := proto.Unmarshal(m.Value, msg); err != nil {
 		s.log.WarnMsg("proto.Unmarshal", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
 		return
 	}
 
 	productUUID, err := uuid.FromString(msg.GetProductID())
 	if err != nil {
 		s.log.WarnMsg("uuid.FromString", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
-	command := commands.NewDeleteProductCommand(productUUID)
+	command := commands.NewDeleteProductCommand(productUUID, msg.GetVersion())
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
-	if err := retry.Do(func() error {
-		return s.ps.Commands.DeleteProduct.Handle(ctx, command)
-	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+	if err := s.ps.Commands.DeleteProduct.Handle(ctx, command); err != nil {
+		if errors.Is(err, repository.ErrStaleEvent) {
+			s.commitStaleMessage(ctx, r, m)
+			return
+		}
 		s.log.WarnMsg("DeleteProduct.Handle", err)
-		s.metrics.ErrorKafkaMessages.Inc()
+		s.failures.RetryErrMessage(ctx, r, m, err)
 		return
 	}
 
@@ -3,15 +3,17 @@ package kafka
 import (
 	"context"
 
-	"github.com/avast/retry-go"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/commands"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 )
 
-func (s *readerMessageProcessor) processProductUpdated(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *readerMessageProcessor) processProductUpdated(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
 	s.metrics.UpdateProductKafkaMessages.Inc()
 
 	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "readerMessageProcessor.processProductUpdated")
@@ -20,23 +22,25 @@ func (s *readerMessageProcessor) processProductUpdated(ctx context.Context, r *k
 	msg := &kafkaMessages.ProductUpdated{}
 	if err := proto.Unmarshal(m.Value, msg); err != nil {
 		s.log.WarnMsg("proto.Unmarshal", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
 		return
 	}
 
 	p := msg.GetProduct()
-	command := commands.NewUpdateProductCommand(p.GetProductID(), p.GetName(), p.GetDescription(), p.GetPrice(), p.GetUpdatedAt().AsTime())
+	command := commands.NewUpdateProductCommand(p.GetProductID(), p.GetName(), p.GetDescription(), p.GetPrice(), p.GetUpdatedAt().AsTime(), p.GetVersion())
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
-	if err := retry.Do(func() error {
-		return s.ps.Commands.UpdateProduct.Handle(ctx, command)
-	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+	if err := s.ps.Commands.UpdateProduct.Handle(ctx, command); err != nil {
+		if errors.Is(err, repository.ErrStaleEvent) {
+			s.commitStaleMessage(ctx, r, m)
+			return
+		}
 		s.log.WarnMsg("UpdateProduct.Handle", err)
-		s.metrics.ErrorKafkaMessages.Inc()
+		s.failures.RetryErrMessage(ctx, r, m, err)
 		return
 	}
 
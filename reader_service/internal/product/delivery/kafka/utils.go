@@ -2,10 +2,12 @@ package kafka
 
 import (
 	"context"
+
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/segmentio/kafka-go"
 )
 
-func (s *readerMessageProcessor) commitMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *readerMessageProcessor) commitMessage(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
 	s.metrics.SuccessKafkaMessages.Inc()
 	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
 
@@ -18,8 +20,10 @@ func (s *readerMessageProcessor) logProcessMessage(m kafka.Message, workerID int
 	s.log.KafkaProcessMessage(m.Topic, m.Partition, string(m.Value), workerID, m.Offset, m.Time)
 }
 
-func (s *readerMessageProcessor) commitErrMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
-	s.metrics.ErrorKafkaMessages.Inc()
+// commitStaleMessage commits event older than the stored product without applying it
+func (s *readerMessageProcessor) commitStaleMessage(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
+	s.metrics.StaleKafkaMessages.Inc()
+	s.log.Infof("skipped stale event topic: %s, partition: %d, offset: %d", m.Topic, m.Partition, m.Offset)
 	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
 	if err := r.CommitMessages(ctx, m); err != nil {
 		s.log.WarnMsg("commitMessage", err)
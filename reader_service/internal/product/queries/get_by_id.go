@@ -2,12 +2,20 @@ package queries
 
 import (
 	"context"
+	"time"
 
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/reader_service/config"
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	productLoadTimeout = 5 * time.Second
 )
 
 type GetProductByIdHandler interface {
@@ -19,6 +27,7 @@ type getProductByIdHandler struct {
 	cfg       *config.Config
 	mongoRepo repository.Repository
 	redisRepo repository.CacheRepository
+	loads     singleflight.Group
 }
 
 func NewGetProductByIdHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository) *getProductByIdHandler {
@@ -29,15 +38,37 @@ func (q *getProductByIdHandler) Handle(ctx context.Context, query *GetProductByI
 	span, ctx := opentracing.StartSpanFromContext(ctx, "getProductByIdHandler.Handle")
 	defer span.Finish()
 
-	if product, err := q.redisRepo.GetProduct(ctx, query.ProductID.String()); err == nil && product != nil {
+	key := query.ProductID.String()
+	product, err := q.redisRepo.GetProduct(ctx, key)
+	if err == nil {
 		return product, nil
 	}
+	if errors.Is(err, repository.ErrCachedNotFound) {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, "redisRepo.GetProduct")
+	}
 
-	product, err := q.mongoRepo.GetProductById(ctx, query.ProductID)
+	// concurrent misses of the same id share one mongo lookup, it does not use the ctx of the caller that started it
+	// so other callers do not fail when that caller is cancelled
+	loaded, err, _ := q.loads.Do(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), productLoadTimeout)
+		defer cancel()
+
+		product, err := q.mongoRepo.GetProductById(ctx, query.ProductID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				q.redisRepo.PutNotFound(ctx, key)
+			}
+			return nil, err
+		}
+
+		q.redisRepo.PutProduct(ctx, product.ProductID, product)
+		return product, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	q.redisRepo.PutProduct(ctx, product.ProductID, product)
-	return product, nil
+	// callers sharing the lookup get their own copy
+	shared := *loaded.(*models.Product)
+	return &shared, nil
 }
@@ -0,0 +1,87 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	"github.com/opentracing/opentracing-go"
+)
+
+type GetProductsByIdsHandler interface {
+	Handle(ctx context.Context, query *GetProductsByIdsQuery) (*models.ProductsBatch, error)
+}
+
+type getProductsByIdsHandler struct {
+	log       logger.Logger
+	cfg       *config.Config
+	mongoRepo repository.Repository
+	redisRepo repository.CacheRepository
+}
+
+func NewGetProductsByIdsHandler(log logger.Logger, cfg *config.Config, mongoRepo repository.Repository, redisRepo repository.CacheRepository) *getProductsByIdsHandler {
+	return &getProductsByIdsHandler{log: log, cfg: cfg, mongoRepo: mongoRepo, redisRepo: redisRepo}
+}
+
+// Handle reads the products through the cache and loads all misses with one mongo query,
+// products keep the order of the requested ids and duplicates are returned once
+func (q *getProductsByIdsHandler) Handle(ctx context.Context, query *GetProductsByIdsQuery) (*models.ProductsBatch, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "getProductsByIdsHandler.Handle")
+	defer span.Finish()
+
+	ids := make([]string, 0, len(query.ProductIDs))
+	seen := make(map[string]struct{}, len(query.ProductIDs))
+	for _, productID := range query.ProductIDs {
+		id := productID.String()
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	cached, err := q.redisRepo.GetProducts(ctx, ids)
+	if err != nil {
+		cached = make(map[string]*models.Product)
+	}
+
+	misses := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := cached[id]; !ok {
+			misses = append(misses, id)
+		}
+	}
+
+	if len(misses) > 0 {
+		loaded, err := q.mongoRepo.GetProductsByIds(ctx, misses)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range loaded {
+			cached[product.ProductID] = product
+			q.redisRepo.PutProduct(ctx, product.ProductID, product)
+		}
+		for _, id := range misses {
+			if _, ok := cached[id]; !ok {
+				cached[id] = nil
+				q.redisRepo.PutNotFound(ctx, id)
+			}
+		}
+	}
+
+	batch := &models.ProductsBatch{
+		Products:    make([]*models.Product, 0, len(ids)),
+		NotFoundIDs: make([]string, 0),
+	}
+	for _, id := range ids {
+		if product := cached[id]; product != nil {
+			batch.Products = append(batch.Products, product)
+			continue
+		}
+		batch.NotFoundIDs = append(batch.NotFoundIDs, id)
+	}
+
+	return batch, nil
+}
@@ -1,17 +1,30 @@
 package queries
 
 import (
+	"github.com/herhu/Microservices-PR/pkg/search"
 	"github.com/herhu/Microservices-PR/pkg/utils"
 	uuid "github.com/satori/go.uuid"
 )
 
 type ProductQueries struct {
-	GetProductById GetProductByIdHandler
-	SearchProduct  SearchProductHandler
+	GetProductById   GetProductByIdHandler
+	GetProductsByIds GetProductsByIdsHandler
+	SearchProduct    SearchProductHandler
+	WatchProducts    WatchProductsHandler
 }
 
-func NewProductQueries(getProductById GetProductByIdHandler, searchProduct SearchProductHandler) *ProductQueries {
-	return &ProductQueries{GetProductById: getProductById, SearchProduct: searchProduct}
+func NewProductQueries(
+	getProductById GetProductByIdHandler,
+	getProductsByIds GetProductsByIdsHandler,
+	searchProduct SearchProductHandler,
+	watchProducts WatchProductsHandler,
+) *ProductQueries {
+	return &ProductQueries{
+		GetProductById:   getProductById,
+		GetProductsByIds: getProductsByIds,
+		SearchProduct:    searchProduct,
+		WatchProducts:    watchProducts,
+	}
 }
 
 type GetProductByIdQuery struct {
@@ -22,11 +35,39 @@ func NewGetProductByIdQuery(productID uuid.UUID) *GetProductByIdQuery {
 	return &GetProductByIdQuery{ProductID: productID}
 }
 
+type GetProductsByIdsQuery struct {
+	ProductIDs []uuid.UUID `json:"productIds" validate:"required,min=1"`
+}
+
+func NewGetProductsByIdsQuery(productIDs []uuid.UUID) *GetProductsByIdsQuery {
+	return &GetProductsByIdsQuery{ProductIDs: productIDs}
+}
+
 type SearchProductQuery struct {
 	Text       string            `json:"text"`
 	Pagination *utils.Pagination `json:"pagination"`
+	Filter     search.Filter     `json:"filter"`
+	CursorMode bool              `json:"cursorMode"`
+	Cursor     string            `json:"cursor"`
+}
+
+func NewSearchProductQuery(text string, pagination *utils.Pagination, filter search.Filter) *SearchProductQuery {
+	return &SearchProductQuery{Text: text, Pagination: pagination, Filter: filter}
+}
+
+// WithCursor switches the query to cursor mode, an empty cursor requests the first page
+func (q *SearchProductQuery) WithCursor(cursor string) *SearchProductQuery {
+	q.CursorMode = true
+	q.Cursor = cursor
+	return q
+}
+
+type WatchProductsQuery struct {
+	ProductIDs  []uuid.UUID `json:"productIds"`
+	Search      string      `json:"search"`
+	ResumeToken string      `json:"resumeToken"`
 }
 
-func NewSearchProductQuery(text string, pagination *utils.Pagination) *SearchProductQuery {
-	return &SearchProductQuery{Text: text, Pagination: pagination}
+func NewWatchProductsQuery(productIDs []uuid.UUID, search string, resumeToken string) *WatchProductsQuery {
+	return &WatchProductsQuery{ProductIDs: productIDs, Search: search, ResumeToken: resumeToken}
 }
@@ -2,11 +2,14 @@ package queries
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/search"
 	"github.com/herhu/Microservices-PR/reader_service/config"
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	"github.com/pkg/errors"
 )
 
 type SearchProductHandler interface {
@@ -25,5 +28,72 @@ func NewSearchProductHandler(log logger.Logger, cfg *config.Config, mongoRepo re
 }
 
 func (s *searchProductHandler) Handle(ctx context.Context, query *SearchProductQuery) (*models.ProductsList, error) {
-	return s.mongoRepo.Search(ctx, query.Text, query.Pagination)
+	searchQuery, err := search.Parse(query.Text)
+	if err != nil {
+		return nil, err
+	}
+	searchQuery.Filter = query.Filter
+	searchQuery.Sort, err = search.ParseSort(query.Pagination.GetOrderBy())
+	if err != nil {
+		return nil, err
+	}
+	if err := searchQuery.Validate(); err != nil {
+		return nil, err
+	}
+
+	// the generation is read before querying mongo, a write during the query moves to the next generation
+	// so the page can never be served after the write
+	generation, err := s.redisRepo.SearchGeneration(ctx)
+	if err != nil {
+		s.log.WarnMsg("redisRepo.SearchGeneration", err)
+		return s.search(ctx, searchQuery, query)
+	}
+
+	key := searchCacheKey(searchQuery, query)
+	if list, err := s.redisRepo.GetSearch(ctx, generation, key); err == nil {
+		return list, nil
+	}
+
+	list, err := s.search(ctx, searchQuery, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.redisRepo.PutSearch(ctx, generation, key, list)
+	return list, nil
+}
+
+func (s *searchProductHandler) search(ctx context.Context, searchQuery *search.Query, query *SearchProductQuery) (*models.ProductsList, error) {
+	if query.CursorMode {
+		return s.searchAfter(ctx, searchQuery, query)
+	}
+	return s.mongoRepo.Search(ctx, searchQuery, query.Pagination)
+}
+
+func searchCacheKey(searchQuery *search.Query, query *SearchProductQuery) string {
+	if query.CursorMode {
+		return fmt.Sprintf("%s|cursor|%d|%s", searchQuery.Key(), query.Pagination.GetSize(), query.Cursor)
+	}
+	return fmt.Sprintf("%s|page|%d|%d", searchQuery.Key(), query.Pagination.GetSize(), query.Pagination.GetPage())
+}
+
+func (s *searchProductHandler) searchAfter(ctx context.Context, searchQuery *search.Query, query *SearchProductQuery) (*models.ProductsList, error) {
+	size := query.Pagination.GetSize()
+	if size <= 0 {
+		size = search.DefaultCursorSize
+	}
+
+	if query.Cursor == "" {
+		return s.mongoRepo.SearchAfter(ctx, searchQuery, nil, size)
+	}
+
+	after, err := search.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if !after.Matches(searchQuery.ResolvedSort()) {
+		return nil, errors.Wrap(search.ErrInvalidQuery, "cursor was issued for a different sort")
+	}
+
+	return s.mongoRepo.SearchAfter(ctx, searchQuery, after, size)
 }
@@ -0,0 +1,114 @@
+package queries
+
+import (
+	"context"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/search"
+	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+)
+
+const (
+	watchReadCount = 100
+	// watchPollInterval reads the feed even without an append notification, in case the feed tail is failing
+	watchPollInterval = 5 * time.Second
+)
+
+type WatchProductsHandler interface {
+	Handle(ctx context.Context, query *WatchProductsQuery, started func(resumeToken string) error, send func(change *models.ProductChange) error) error
+}
+
+type watchProductsHandler struct {
+	log        logger.Logger
+	cfg        *config.Config
+	changeFeed repository.ChangeFeed
+}
+
+func NewWatchProductsHandler(log logger.Logger, cfg *config.Config, changeFeed repository.ChangeFeed) *watchProductsHandler {
+	return &watchProductsHandler{log: log, cfg: cfg, changeFeed: changeFeed}
+}
+
+// Handle sends matching changes until the context is done. Without a resume token only changes applied after
+// the call are sent, started is called with the position the watch starts from before any change is read.
+// Deletes carry no product, so with only a text filter every delete is sent.
+func (w *watchProductsHandler) Handle(
+	ctx context.Context,
+	query *WatchProductsQuery,
+	started func(resumeToken string) error,
+	send func(change *models.ProductChange) error,
+) error {
+	textQuery, err := search.Parse(query.Search)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]struct{}, len(query.ProductIDs))
+	for _, id := range query.ProductIDs {
+		ids[id.String()] = struct{}{}
+	}
+
+	after := query.ResumeToken
+	if after == "" {
+		if after, err = w.changeFeed.Latest(ctx); err != nil {
+			return err
+		}
+	} else if err := w.changeFeed.CheckResumeToken(ctx, after); err != nil {
+		return err
+	}
+
+	if err := started(after); err != nil {
+		return err
+	}
+
+	poll := time.NewTicker(watchPollInterval)
+	defer poll.Stop()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+
+		appended := w.changeFeed.Appended()
+		changes, err := w.changeFeed.Read(ctx, after, watchReadCount)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, change := range changes {
+			after = change.ResumeToken
+			if !watchMatches(ids, textQuery, change) {
+				continue
+			}
+			if err := send(change); err != nil {
+				return err
+			}
+		}
+		if len(changes) == watchReadCount {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-appended:
+		case <-poll.C:
+		}
+	}
+}
+
+func watchMatches(ids map[string]struct{}, textQuery *search.Query, change *models.ProductChange) bool {
+	if len(ids) > 0 {
+		if _, ok := ids[change.ProductID]; !ok {
+			return false
+		}
+	}
+	if change.Product == nil || textQuery.Empty() {
+		return true
+	}
+	return textQuery.Matches(change.Product.Name, change.Product.Description, change.Product.Price)
+}
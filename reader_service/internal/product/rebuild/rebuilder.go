@@ -0,0 +1,294 @@
+package rebuild
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/upcasters"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
+	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	writerService "github.com/herhu/Microservices-PR/writer_service/proto/product_writer"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/segmentio/kafka-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Sources of the rebuilt projection
+const (
+	SourceWriter = "writer"
+	SourceKafka  = "kafka"
+)
+
+const (
+	shadowCollectionSuffix = "_rebuild"
+	writerStreamBatchSize  = 500
+)
+
+// Options From is the time of the first replayed event for the kafka source, zero replays the topics from the first offset.
+// The replay stops when no message is fetched for IdleTimeout.
+type Options struct {
+	Source      string
+	From        time.Time
+	IdleTimeout time.Duration
+}
+
+// replayStart first replayed message of the partitions, the kafka source starts at a time and the writer source
+// at the partition end offsets taken before the writer snapshot. Events of the three topics are not ordered
+// between each other, they are applied by product version and deletes leave versioned tombstones, so the replay
+// order of the topics does not matter.
+type replayStart struct {
+	from    time.Time
+	offsets map[string]map[int]int64
+}
+
+func (s replayStart) skips(m kafka.Message) bool {
+	return m.Time.Before(s.from) || m.Offset < s.offsets[m.Topic][m.Partition]
+}
+
+type Rebuilder struct {
+	log          logger.Logger
+	cfg          *config.Config
+	mongoClient  *mongo.Client
+	redisRepo    repository.CacheRepository
+	broker       kafkaClient.Broker
+	writerClient writerService.WriterServiceClient
+	routes       kafkaClient.TopicRoutes
+}
+
+// NewRebuilder products projection rebuilder, writerClient is only used by the writer source
+func NewRebuilder(
+	log logger.Logger,
+	cfg *config.Config,
+	mongoClient *mongo.Client,
+	redisRepo repository.CacheRepository,
+	broker kafkaClient.Broker,
+	writerClient writerService.WriterServiceClient,
+) *Rebuilder {
+	return &Rebuilder{log: log, cfg: cfg, mongoClient: mongoClient, redisRepo: redisRepo, broker: broker, writerClient: writerClient, routes: cfg.KafkaTopics.Routes()}
+}
+
+// Run builds the projection in a shadow collection from the source and the product topics replayed until idle,
+// then atomically swaps it with the products collection and flushes the products cache.
+// The replay continues into the swapped collection to apply events the live consumers wrote to the old collection before the swap.
+func (r *Rebuilder) Run(ctx context.Context, opts Options) error {
+	startedAt := time.Now().UTC()
+	shadow := r.cfg.MongoCollections.Products + shadowCollectionSuffix
+
+	if err := r.database().Collection(shadow).Drop(ctx); err != nil {
+		return errors.Wrap(err, "Drop")
+	}
+	shadowRepo := repository.NewMongoRepositoryWithCollection(r.log, r.cfg, r.mongoClient, shadow)
+	if err := shadowRepo.EnsureIndexes(ctx); err != nil {
+		return errors.Wrap(err, "EnsureIndexes")
+	}
+
+	start := replayStart{from: opts.From}
+	switch opts.Source {
+	case SourceWriter:
+		// every event published before the end offsets is of a change the writer snapshot already contains
+		offsets, err := r.broker.EndOffsets(ctx, r.topics()...)
+		if err != nil {
+			return errors.Wrap(err, "EndOffsets")
+		}
+		start = replayStart{offsets: offsets}
+
+		loaded, err := r.loadFromWriter(ctx, shadow)
+		if err != nil {
+			return errors.Wrap(err, "loadFromWriter")
+		}
+		r.log.Infof("rebuild loaded %d products from writer into %s", loaded, shadow)
+	case SourceKafka:
+	default:
+		return errors.Errorf("unknown rebuild source: %s", opts.Source)
+	}
+
+	reader := r.broker.NewReader(r.topics(), fmt.Sprintf("%s_rebuild_%d", r.cfg.Kafka.GroupID, startedAt.Unix()))
+	defer reader.Close() // nolint: errcheck
+
+	replayed, err := r.replay(ctx, reader, shadowRepo, start, opts.IdleTimeout)
+	if err != nil {
+		return errors.Wrap(err, "replay")
+	}
+	r.log.Infof("rebuild replayed %d events from time: %v, offsets: %v into %s", replayed, start.from, start.offsets, shadow)
+
+	if err := r.swap(ctx, shadow); err != nil {
+		return errors.Wrap(err, "swap")
+	}
+	r.log.Infof("rebuild swapped %s with %s", shadow, r.cfg.MongoCollections.Products)
+
+	liveRepo := repository.NewMongoRepository(r.log, r.cfg, r.mongoClient)
+	caughtUp, err := r.replay(ctx, reader, liveRepo, start, opts.IdleTimeout)
+	if err != nil {
+		return errors.Wrap(err, "replay")
+	}
+	r.log.Infof("rebuild caught up %d events after swap", caughtUp)
+
+	r.redisRepo.DelAllProducts(ctx)
+	r.redisRepo.InvalidateSearches(ctx)
+	return nil
+}
+
+func (r *Rebuilder) loadFromWriter(ctx context.Context, collection string) (int, error) {
+	stream, err := r.writerClient.StreamProducts(ctx, &writerService.StreamProductsReq{BatchSize: writerStreamBatchSize})
+	if err != nil {
+		return 0, errors.Wrap(err, "writerClient.StreamProducts")
+	}
+
+	loaded := 0
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return loaded, nil
+		}
+		if err != nil {
+			return loaded, errors.Wrap(err, "stream.Recv")
+		}
+		if len(res.GetProducts()) == 0 {
+			continue
+		}
+
+		documents := make([]interface{}, 0, len(res.GetProducts()))
+		for _, product := range res.GetProducts() {
+			documents = append(documents, &models.Product{
+				ProductID:   product.GetProductID(),
+				Name:        product.GetName(),
+				Description: product.GetDescription(),
+				Price:       product.GetPrice(),
+				CreatedAt:   timeOrZero(product.GetCreatedAt()),
+				UpdatedAt:   timeOrZero(product.GetUpdatedAt()),
+				Version:     product.GetVersion(),
+			})
+		}
+
+		if _, err := r.database().Collection(collection).InsertMany(ctx, documents); err != nil {
+			return loaded, errors.Wrap(err, "InsertMany")
+		}
+		loaded += len(documents)
+	}
+}
+
+// replay applies fetched events until the reader is idle, events before the start are skipped
+// and messages which can not be applied are logged and skipped
+func (r *Rebuilder) replay(ctx context.Context, reader kafkaClient.Reader, repo repository.Repository, start replayStart, idleTimeout time.Duration) (int, error) {
+	replayed := 0
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, idleTimeout)
+		m, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return replayed, ctx.Err()
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return replayed, nil
+			}
+			return replayed, errors.Wrap(err, "FetchMessage")
+		}
+
+		if start.skips(m) {
+			continue
+		}
+
+		if err := r.apply(ctx, repo, m); err != nil {
+			if errors.Is(err, repository.ErrStaleEvent) {
+				continue
+			}
+			r.log.Warnf("rebuild skipped message topic: %s, partition: %d, offset: %d, err: %v", m.Topic, m.Partition, m.Offset, err)
+			continue
+		}
+		replayed++
+	}
+}
+
+func (r *Rebuilder) apply(ctx context.Context, repo repository.Repository, m kafka.Message) error {
+	envelope, err := kafkaClient.MessageEnvelope(m, r.routes.EventType(m.Topic))
+	if err != nil {
+		return err
+	}
+
+	m, err = upcasters.Products.UpcastMessage(m, envelope)
+	if err != nil {
+		return err
+	}
+
+	switch envelope.EventType {
+	case kafkaMessages.ProductCreatedType:
+		msg := &kafkaMessages.ProductCreated{}
+		if err := proto.Unmarshal(m.Value, msg); err != nil {
+			return errors.Wrap(err, "proto.Unmarshal")
+		}
+		_, err := repo.UpdateProduct(ctx, productFromMessage(msg.GetProduct()))
+		return err
+	case kafkaMessages.ProductUpdatedType:
+		msg := &kafkaMessages.ProductUpdated{}
+		if err := proto.Unmarshal(m.Value, msg); err != nil {
+			return errors.Wrap(err, "proto.Unmarshal")
+		}
+		_, err := repo.UpdateProduct(ctx, productFromMessage(msg.GetProduct()))
+		return err
+	case kafkaMessages.ProductDeletedType:
+		msg := &kafkaMessages.ProductDeleted{}
+		if err := proto.Unmarshal(m.Value, msg); err != nil {
+			return errors.Wrap(err, "proto.Unmarshal")
+		}
+		productUUID, err := uuid.FromString(msg.GetProductID())
+		if err != nil {
+			return errors.Wrap(err, "uuid.FromString")
+		}
+		return repo.DeleteProduct(ctx, productUUID, msg.GetVersion())
+	default:
+		return errors.Errorf("unknown event type: %s", envelope.EventType)
+	}
+}
+
+// swap renames the shadow collection to the products collection replacing it in one operation
+func (r *Rebuilder) swap(ctx context.Context, shadow string) error {
+	db := r.cfg.Mongo.Db
+	command := bson.D{
+		{Key: "renameCollection", Value: fmt.Sprintf("%s.%s", db, shadow)},
+		{Key: "to", Value: fmt.Sprintf("%s.%s", db, r.cfg.MongoCollections.Products)},
+		{Key: "dropTarget", Value: true},
+	}
+	return r.mongoClient.Database("admin").RunCommand(ctx, command).Err()
+}
+
+func (r *Rebuilder) database() *mongo.Database {
+	return r.mongoClient.Database(r.cfg.Mongo.Db)
+}
+
+func (r *Rebuilder) topics() []string {
+	return []string{
+		r.cfg.KafkaTopics.ProductCreated.TopicName,
+		r.cfg.KafkaTopics.ProductUpdated.TopicName,
+		r.cfg.KafkaTopics.ProductDeleted.TopicName,
+	}
+}
+
+func productFromMessage(product *kafkaMessages.Product) *models.Product {
+	return &models.Product{
+		ProductID:   product.GetProductID(),
+		Name:        product.GetName(),
+		Description: product.GetDescription(),
+		Price:       product.GetPrice(),
+		CreatedAt:   timeOrZero(product.GetCreatedAt()),
+		UpdatedAt:   timeOrZero(product.GetUpdatedAt()),
+		Version:     product.GetVersion(),
+	}
+}
+
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/search"
 	"github.com/herhu/Microservices-PR/pkg/utils"
 	"github.com/herhu/Microservices-PR/reader_service/config"
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
@@ -11,63 +13,95 @@ import (
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoRepository struct {
-	log logger.Logger
-	cfg *config.Config
-	db  *mongo.Client
+	log        logger.Logger
+	cfg        *config.Config
+	db         *mongo.Client
+	collection string
 }
 
 func NewMongoRepository(log logger.Logger, cfg *config.Config, db *mongo.Client) *mongoRepository {
-	return &mongoRepository{log: log, cfg: cfg, db: db}
+	return NewMongoRepositoryWithCollection(log, cfg, db, cfg.MongoCollections.Products)
 }
 
+// NewMongoRepositoryWithCollection repository of products stored in the given collection, used to rebuild the projection
+func NewMongoRepositoryWithCollection(log logger.Logger, cfg *config.Config, db *mongo.Client, collection string) *mongoRepository {
+	return &mongoRepository{log: log, cfg: cfg, db: db, collection: collection}
+}
+
+// EnsureIndexes creates the text index of scripts/init.js and the indexes of the sort fields if missing
+func (p *mongoRepository) EnsureIndexes(ctx context.Context) error {
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "description", Value: 1}}},
+		{Keys: bson.D{{Key: "$**", Value: "text"}}},
+		{Keys: bson.D{{Key: "price", Value: 1}}},
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "updatedAt", Value: 1}}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "CreateMany")
+	}
+	return nil
+}
+
+// CreateProduct inserts the product unless a document of the same or a newer version is stored
 func (p *mongoRepository) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.CreateProduct")
 	defer span.Finish()
 
-	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.cfg.MongoCollections.Products)
-
-	_, err := collection.InsertOne(ctx, product, &options.InsertOneOptions{})
+	created, err := p.upsertIfNewer(ctx, product)
 	if err != nil {
 		p.traceErr(span, err)
-		return nil, errors.Wrap(err, "InsertOne")
+		return nil, err
 	}
 
-	return product, nil
+	return created, nil
 }
 
+// UpdateProduct updates the product when the stored document is older, returns ErrStaleEvent otherwise
 func (p *mongoRepository) UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.UpdateProduct")
 	defer span.Finish()
 
-	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.cfg.MongoCollections.Products)
+	updated, err := p.upsertIfNewer(ctx, product)
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, err
+	}
 
-	ops := options.FindOneAndUpdate()
-	ops.SetReturnDocument(options.After)
-	ops.SetUpsert(true)
+	return updated, nil
+}
 
-	var updated models.Product
-	if err := collection.FindOneAndUpdate(ctx, bson.M{"_id": product.ProductID}, bson.M{"$set": product}, ops).Decode(&updated); err != nil {
+func (p *mongoRepository) GetProductById(ctx context.Context, uuid uuid.UUID) (*models.Product, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.GetProductById")
+	defer span.Finish()
+
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
+
+	var product models.Product
+	if err := collection.FindOne(ctx, bson.M{"_id": uuid.String(), "deleted": bson.M{"$ne": true}}).Decode(&product); err != nil {
 		p.traceErr(span, err)
 		return nil, errors.Wrap(err, "Decode")
 	}
 
-	return &updated, nil
+	return &product, nil
 }
 
-func (p *mongoRepository) GetProductById(ctx context.Context, uuid uuid.UUID) (*models.Product, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.GetProductById")
+// GetStoredProduct returns the stored document of the product including its tombstone when it was deleted
+func (p *mongoRepository) GetStoredProduct(ctx context.Context, productID string) (*models.Product, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.GetStoredProduct")
 	defer span.Finish()
 
-	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.cfg.MongoCollections.Products)
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
 
 	var product models.Product
-	if err := collection.FindOne(ctx, bson.M{"_id": uuid.String()}).Decode(&product); err != nil {
+	if err := collection.FindOne(ctx, bson.M{"_id": productID}).Decode(&product); err != nil {
 		p.traceErr(span, err)
 		return nil, errors.Wrap(err, "Decode")
 	}
@@ -75,27 +109,60 @@ func (p *mongoRepository) GetProductById(ctx context.Context, uuid uuid.UUID) (*
 	return &product, nil
 }
 
-func (p *mongoRepository) DeleteProduct(ctx context.Context, uuid uuid.UUID) error {
+// DeleteProduct replaces the product with a tombstone of the deleted version so older events can not recreate it,
+// returns ErrStaleEvent when a newer version is stored
+func (p *mongoRepository) DeleteProduct(ctx context.Context, uuid uuid.UUID, version int64) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.DeleteProduct")
 	defer span.Finish()
 
-	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.cfg.MongoCollections.Products)
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
+
+	update := bson.M{
+		"$set":   bson.M{"deleted": true, "version": version, "updatedAt": time.Now().UTC()},
+		"$unset": bson.M{"name": "", "description": "", "price": ""},
+	}
 
-	return collection.FindOneAndDelete(ctx, bson.M{"_id": uuid.String()}).Err()
+	if _, err := collection.UpdateOne(ctx, newerEventFilter(uuid.String(), version, time.Time{}), update, options.Update().SetUpsert(true)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrStaleEvent
+		}
+		p.traceErr(span, err)
+		return errors.Wrap(err, "UpdateOne")
+	}
+
+	return nil
 }
 
-func (p *mongoRepository) Search(ctx context.Context, search string, pagination *utils.Pagination) (*models.ProductsList, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.Search")
+// GetProductsByIds returns the existing products of the ids in one $in query, missing ids are skipped
+func (p *mongoRepository) GetProductsByIds(ctx context.Context, ids []string) ([]*models.Product, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.GetProductsByIds")
 	defer span.Finish()
 
-	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.cfg.MongoCollections.Products)
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
 
-	filter := bson.D{
-		{Key: "$or", Value: bson.A{
-			bson.D{{Key: "name", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
-			bson.D{{Key: "description", Value: primitive.Regex{Pattern: search, Options: "gi"}}},
-		}},
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted": bson.M{"$ne": true}})
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Find")
 	}
+	defer cursor.Close(ctx) // nolint: errcheck
+
+	products := make([]*models.Product, 0, len(ids))
+	if err := cursor.All(ctx, &products); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+
+	return products, nil
+}
+
+func (p *mongoRepository) Search(ctx context.Context, query *search.Query, pagination *utils.Pagination) (*models.ProductsList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.Search")
+	defer span.Finish()
+
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
+
+	filter := searchFilter(query)
 
 	count, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -106,15 +173,19 @@ func (p *mongoRepository) Search(ctx context.Context, search string, pagination
 		return &models.ProductsList{Products: make([]*models.Product, 0)}, nil
 	}
 
-	limit := int64(pagination.GetLimit())
-	skip := int64(pagination.GetOffset())
-	cursor, err := collection.Find(ctx, filter, &options.FindOptions{
-		Limit: &limit,
-		Skip:  &skip,
-	})
+	pipeline := searchPipeline(query, filter)
+	if sort := searchSort(query); len(sort) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$skip", Value: int64(pagination.GetOffset())}})
+	if limit := int64(pagination.GetLimit()); limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		p.traceErr(span, err)
-		return nil, errors.Wrap(err, "Find")
+		return nil, errors.Wrap(err, "Aggregate")
 	}
 	defer cursor.Close(ctx) // nolint: errcheck
 
@@ -138,6 +209,107 @@ func (p *mongoRepository) Search(ctx context.Context, search string, pagination
 	return models.NewProductListWithPagination(products, count, pagination), nil
 }
 
+// SearchAfter returns the page after the cursor ordered by a sort key and the id, the first page has a nil cursor.
+// Unlike Search it neither counts nor skips so deep pages stay cheap and stable under concurrent writes.
+func (p *mongoRepository) SearchAfter(ctx context.Context, query *search.Query, after *search.Cursor, size int) (*models.ProductsList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "mongoRepository.SearchAfter")
+	defer span.Finish()
+
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
+
+	pipeline := searchPipeline(query, searchFilter(query))
+	if after != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: cursorFilter(after)}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: cursorSort(query)}},
+		bson.D{{Key: "$limit", Value: int64(size + 1)}},
+	)
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "Aggregate")
+	}
+	defer cursor.Close(ctx) // nolint: errcheck
+
+	products := make([]*models.Product, 0, size+1)
+	var lastScore float64
+	for cursor.Next(ctx) {
+		var prod scoredProduct
+		if err := cursor.Decode(&prod); err != nil {
+			p.traceErr(span, err)
+			return nil, errors.Wrap(err, "Decode")
+		}
+		products = append(products, &prod.Product)
+		lastScore = prod.Score
+		if len(products) == size {
+			break
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		p.traceErr(span, err)
+		return nil, errors.Wrap(err, "cursor.Err")
+	}
+
+	hasMore := len(products) == size && cursor.Next(ctx)
+	if !hasMore {
+		return models.NewProductListWithCursor(products, size, false, ""), nil
+	}
+
+	sort := query.ResolvedSort()
+	last := products[len(products)-1]
+	nextCursor, err := search.NewCursor(sort, sortValue(sort, last, lastScore), last.ProductID).Encode()
+	if err != nil {
+		p.traceErr(span, err)
+		return nil, err
+	}
+
+	return models.NewProductListWithCursor(products, size, true, nextCursor), nil
+}
+
+// upsertIfNewer the product document is only written when the event is newer than the stored document,
+// otherwise the upsert conflicts with the existing id and ErrStaleEvent is returned
+func (p *mongoRepository) upsertIfNewer(ctx context.Context, product *models.Product) (*models.Product, error) {
+	collection := p.db.Database(p.cfg.Mongo.Db).Collection(p.collection)
+
+	ops := options.FindOneAndUpdate()
+	ops.SetReturnDocument(options.After)
+	ops.SetUpsert(true)
+
+	update := bson.M{"$set": product, "$unset": bson.M{"deleted": ""}}
+
+	var updated models.Product
+	if err := collection.FindOneAndUpdate(ctx, newerEventFilter(product.ProductID, product.Version, product.UpdatedAt), update, ops).Decode(&updated); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrStaleEvent
+		}
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	return &updated, nil
+}
+
+// newerEventFilter matches the product document older than the event version,
+// events without version are compared by UpdatedAt
+func newerEventFilter(productID string, version int64, updatedAt time.Time) bson.M {
+	switch {
+	case version > 0:
+		return bson.M{"_id": productID, "$or": bson.A{
+			bson.M{"version": bson.M{"$lt": version}},
+			bson.M{"version": bson.M{"$exists": false}},
+		}}
+	case !updatedAt.IsZero():
+		return bson.M{"_id": productID, "$or": bson.A{
+			bson.M{"updatedAt": bson.M{"$lt": updatedAt}},
+			bson.M{"updatedAt": bson.M{"$exists": false}},
+		}}
+	default:
+		return bson.M{"_id": productID}
+	}
+}
+
 func (p *mongoRepository) traceErr(span opentracing.Span, err error) {
 	span.SetTag("error", true)
 	span.LogKV("error_code", err.Error())
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	redisChangesStreamKey      = "reader:changes"
+	defaultChangesStreamMaxLen = 100000
+
+	changeFeedField = "change"
+
+	changesTailCount   = 100
+	changesTailBlock   = 5 * time.Second
+	changesTailBackoff = time.Second
+)
+
+var (
+	// ErrResumeTokenExpired the changes after the token were trimmed from the feed
+	ErrResumeTokenExpired = errors.New("resume token expired")
+	// ErrInvalidResumeToken the token is not a change feed position
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+)
+
+// redisChangeFeed keeps applied changes in a capped redis stream shared by all reader instances,
+// stream entry ids are the resume tokens. One blocking read tails the stream for every watcher of the instance,
+// so watchers only hold a pool connection for their non blocking reads.
+type redisChangeFeed struct {
+	log         logger.Logger
+	cfg         *config.Config
+	redisClient redis.UniversalClient
+	mu          sync.Mutex
+	appended    chan struct{}
+}
+
+func NewRedisChangeFeed(log logger.Logger, cfg *config.Config, redisClient redis.UniversalClient) *redisChangeFeed {
+	return &redisChangeFeed{log: log, cfg: cfg, redisClient: redisClient, appended: make(chan struct{})}
+}
+
+// Run tails the stream until the context is done and wakes the watchers waiting on Appended
+func (f *redisChangeFeed) Run(ctx context.Context) {
+	last := "$"
+	for ctx.Err() == nil {
+		streams, err := f.redisClient.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{f.streamKey(), last},
+			Count:   changesTailCount,
+			Block:   changesTailBlock,
+		}).Result()
+		if err != nil {
+			if err == redis.Nil || ctx.Err() != nil {
+				continue
+			}
+			f.log.WarnMsg("redisClient.XRead", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(changesTailBackoff):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			if len(stream.Messages) > 0 {
+				last = stream.Messages[len(stream.Messages)-1].ID
+			}
+		}
+		f.notify()
+	}
+}
+
+// Appended returns a channel closed once changes are appended after the call,
+// watchers take it before reading so a change appended meanwhile is not missed
+func (f *redisChangeFeed) Appended() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.appended
+}
+
+func (f *redisChangeFeed) notify() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	close(f.appended)
+	f.appended = make(chan struct{})
+}
+
+// Publish appends the change, a failure fails the consumed message so it is retried.
+// The correlation id of the consumed message lets the gateway recognize the changes of its own requests.
+func (f *redisChangeFeed) Publish(ctx context.Context, change *models.ProductChange) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisChangeFeed.Publish")
+	defer span.Finish()
+
+	if change.CorrelationID == "" {
+		change.CorrelationID = kafkaClient.CorrelationIDFromContext(ctx)
+	}
+
+	changeBytes, err := json.Marshal(change)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	err = f.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: f.streamKey(),
+		MaxLen: f.maxLen(),
+		Approx: true,
+		Values: map[string]interface{}{changeFeedField: changeBytes},
+	}).Err()
+	if err != nil {
+		return errors.Wrap(err, "redisClient.XAdd")
+	}
+	return nil
+}
+
+// Latest returns the token of the newest change, watching from it only returns later changes
+func (f *redisChangeFeed) Latest(ctx context.Context) (string, error) {
+	entries, err := f.redisClient.XRevRangeN(ctx, f.streamKey(), "+", "-", 1).Result()
+	if err != nil {
+		return "", errors.Wrap(err, "redisClient.XRevRangeN")
+	}
+	if len(entries) == 0 {
+		return "0-0", nil
+	}
+	return entries[0].ID, nil
+}
+
+// CheckResumeToken returns ErrResumeTokenExpired when changes after the token may have been trimmed
+func (f *redisChangeFeed) CheckResumeToken(ctx context.Context, token string) error {
+	tokenID, err := parseStreamID(token)
+	if err != nil {
+		return err
+	}
+
+	entries, err := f.redisClient.XRangeN(ctx, f.streamKey(), "-", "+", 1).Result()
+	if err != nil {
+		return errors.Wrap(err, "redisClient.XRangeN")
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	oldestID, err := parseStreamID(entries[0].ID)
+	if err != nil {
+		return err
+	}
+	if tokenID.before(oldestID) {
+		return ErrResumeTokenExpired
+	}
+	return nil
+}
+
+// Read returns up to count changes after the token without blocking
+func (f *redisChangeFeed) Read(ctx context.Context, after string, count int64) ([]*models.ProductChange, error) {
+	streams, err := f.redisClient.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{f.streamKey(), after},
+		Count:   count,
+		Block:   -1,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "redisClient.XRead")
+	}
+
+	changes := make([]*models.ProductChange, 0, count)
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			value, ok := message.Values[changeFeedField].(string)
+			if !ok {
+				continue
+			}
+			var change models.ProductChange
+			if err := json.Unmarshal([]byte(value), &change); err != nil {
+				f.log.WarnMsg("json.Unmarshal", err)
+				continue
+			}
+			change.ResumeToken = message.ID
+			changes = append(changes, &change)
+		}
+	}
+
+	return changes, nil
+}
+
+func (f *redisChangeFeed) streamKey() string {
+	if f.cfg.ServiceSettings.RedisChangesStreamKey != "" {
+		return f.cfg.ServiceSettings.RedisChangesStreamKey
+	}
+	return redisChangesStreamKey
+}
+
+func (f *redisChangeFeed) maxLen() int64 {
+	if f.cfg.ServiceSettings.RedisChangesMaxLen > 0 {
+		return f.cfg.ServiceSettings.RedisChangesMaxLen
+	}
+	return defaultChangesStreamMaxLen
+}
+
+type streamID struct {
+	ms  uint64
+	seq uint64
+}
+
+func (id streamID) before(other streamID) bool {
+	return id.ms < other.ms || (id.ms == other.ms && id.seq < other.seq)
+}
+
+func parseStreamID(id string) (streamID, error) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return streamID{}, ErrInvalidResumeToken
+	}
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return streamID{}, ErrInvalidResumeToken
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return streamID{}, ErrInvalidResumeToken
+	}
+	return streamID{ms: ms, seq: seq}, nil
+}
@@ -1,12 +1,19 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/reader_service/config"
+	"github.com/herhu/Microservices-PR/reader_service/internal/metrics"
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -14,21 +21,61 @@ import (
 
 const (
 	redisProductPrefixKey = "reader:product"
+	redisSearchPrefixKey  = "reader:search"
+
+	defaultProductTTL  = 10 * time.Minute
+	defaultNotFoundTTL = 30 * time.Second
+	defaultSearchTTL   = time.Minute
+
+	scanBatchSize = 500
 )
 
+var (
+	// ErrCacheMiss the product is not cached
+	ErrCacheMiss = errors.New("product cache miss")
+	// ErrCachedNotFound the product is cached as not existing
+	ErrCachedNotFound = errors.New("product cached as not found")
+
+	// notFoundMarker value of negative cache entries, it never decodes as a product
+	notFoundMarker = []byte("-")
+)
+
+// cachedTombstone value cached for deleted products, its version keeps putIfNewerScript from caching older products again
+type cachedTombstone struct {
+	Version int64 `json:"version"`
+	Deleted bool  `json:"deleted"`
+}
+
+// putIfNewerScript sets the key with a ttl in milliseconds unless the cached product or tombstone has a greater version,
+// negative entries are always replaced
+var putIfNewerScript = redis.NewScript(`
+local cached = redis.call('GET', KEYS[1])
+if cached then
+	local ok, product = pcall(cjson.decode, cached)
+	if ok and type(product) == 'table' and product.version and tonumber(product.version) > tonumber(ARGV[2]) then
+		return 0
+	end
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+return 1
+`)
+
 type redisRepository struct {
 	log         logger.Logger
 	cfg         *config.Config
 	redisClient redis.UniversalClient
+	metrics     *metrics.ReaderServiceMetrics
 }
 
-func NewRedisRepository(log logger.Logger, cfg *config.Config, redisClient redis.UniversalClient) *redisRepository {
-	return &redisRepository{log: log, cfg: cfg, redisClient: redisClient}
+func NewRedisRepository(log logger.Logger, cfg *config.Config, redisClient redis.UniversalClient, metrics *metrics.ReaderServiceMetrics) *redisRepository {
+	return &redisRepository{log: log, cfg: cfg, redisClient: redisClient, metrics: metrics}
 }
 
+// PutProduct caches the product with a jittered ttl unless the cached entry has a newer version
 func (r *redisRepository) PutProduct(ctx context.Context, key string, product *models.Product) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.PutProduct")
 	defer span.Finish()
+	defer r.observe("put", time.Now())
 
 	productBytes, err := json.Marshal(product)
 	if err != nil {
@@ -36,48 +83,296 @@ func (r *redisRepository) PutProduct(ctx context.Context, key string, product *m
 		return
 	}
 
-	if err := r.redisClient.HSetNX(ctx, r.getRedisProductPrefixKey(), key, productBytes).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HSetNX", err)
+	ttl := r.productTTL()
+	if err := putIfNewerScript.Run(ctx, r.redisClient, []string{r.productKey(key)}, productBytes, product.Version, ttl.Milliseconds()).Err(); err != nil {
+		r.log.WarnMsg("putIfNewerScript.Run", err)
+		return
+	}
+	r.log.Debugf("Set key: %s, version: %d, ttl: %v", r.productKey(key), product.Version, ttl)
+}
+
+// PutNotFound caches that the product does not exist, an already cached product is kept
+func (r *redisRepository) PutNotFound(ctx context.Context, key string) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.PutNotFound")
+	defer span.Finish()
+	defer r.observe("put_not_found", time.Now())
+
+	if err := r.redisClient.SetNX(ctx, r.productKey(key), notFoundMarker, r.notFoundTTL()).Err(); err != nil {
+		r.log.WarnMsg("redisClient.SetNX", err)
 		return
 	}
-	r.log.Debugf("HSetNX prefix: %s, key: %s", r.getRedisProductPrefixKey(), key)
+	r.log.Debugf("SetNX not found key: %s", r.productKey(key))
 }
 
+// GetProduct returns ErrCacheMiss when the product is not cached and ErrCachedNotFound for negative entries
 func (r *redisRepository) GetProduct(ctx context.Context, key string) (*models.Product, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.GetProduct")
 	defer span.Finish()
+	defer r.observe("get", time.Now())
 
-	productBytes, err := r.redisClient.HGet(ctx, r.getRedisProductPrefixKey(), key).Bytes()
+	productBytes, err := r.redisClient.Get(ctx, r.productKey(key)).Bytes()
 	if err != nil {
-		if err != redis.Nil {
-			r.log.WarnMsg("redisClient.HGet", err)
+		r.metrics.CacheMisses.Inc()
+		if err == redis.Nil {
+			return nil, ErrCacheMiss
 		}
-		return nil, errors.Wrap(err, "redisClient.HGet")
+		r.log.WarnMsg("redisClient.Get", err)
+		return nil, errors.Wrap(err, "redisClient.Get")
 	}
 
-	var product models.Product
-	if err := json.Unmarshal(productBytes, &product); err != nil {
+	product, err := decodeCachedProduct(productBytes)
+	if err != nil {
+		if errors.Is(err, ErrCachedNotFound) {
+			r.metrics.CacheNegativeHits.Inc()
+			return nil, err
+		}
+		r.metrics.CacheMisses.Inc()
 		return nil, err
 	}
 
-	r.log.Debugf("HGet prefix: %s, key: %s", r.getRedisProductPrefixKey(), key)
-	return &product, nil
+	r.metrics.CacheHits.Inc()
+	r.log.Debugf("Get key: %s", r.productKey(key))
+	return product, nil
+}
+
+// GetProducts reads the keys in one pipeline, the result has cached products and nil for keys cached as not found,
+// misses are absent
+func (r *redisRepository) GetProducts(ctx context.Context, keys []string) (map[string]*models.Product, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.GetProducts")
+	defer span.Finish()
+	defer r.observe("get_many", time.Now())
+
+	pipe := r.redisClient.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, r.productKey(key))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		r.metrics.CacheMisses.Add(float64(len(keys)))
+		r.log.WarnMsg("pipe.Exec", err)
+		return nil, errors.Wrap(err, "pipe.Exec")
+	}
+
+	products := make(map[string]*models.Product, len(keys))
+	for i, cmd := range cmds {
+		productBytes, err := cmd.Bytes()
+		if err != nil {
+			r.metrics.CacheMisses.Inc()
+			continue
+		}
+		product, err := decodeCachedProduct(productBytes)
+		if err != nil {
+			if errors.Is(err, ErrCachedNotFound) {
+				r.metrics.CacheNegativeHits.Inc()
+				products[keys[i]] = nil
+				continue
+			}
+			r.metrics.CacheMisses.Inc()
+			continue
+		}
+		r.metrics.CacheHits.Inc()
+		products[keys[i]] = product
+	}
+
+	return products, nil
 }
 
-func (r *redisRepository) DelProduct(ctx context.Context, key string) {
-	if err := r.redisClient.HDel(ctx, r.getRedisProductPrefixKey(), key).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
+// PutDeleted replaces the cached product with a tombstone of the deleted version, so a concurrent lookup that read
+// the product before the delete can not cache it again
+func (r *redisRepository) PutDeleted(ctx context.Context, key string, version int64) {
+	defer r.observe("put_deleted", time.Now())
+
+	tombstoneBytes, err := json.Marshal(&cachedTombstone{Version: version, Deleted: true})
+	if err != nil {
+		r.log.WarnMsg("json.Marshal", err)
+		return
+	}
+
+	ttl := r.productTTL()
+	if err := putIfNewerScript.Run(ctx, r.redisClient, []string{r.productKey(key)}, tombstoneBytes, version, ttl.Milliseconds()).Err(); err != nil {
+		r.log.WarnMsg("putIfNewerScript.Run", err)
 		return
 	}
-	r.log.Debugf("HDel prefix: %s, key: %s", r.getRedisProductPrefixKey(), key)
+	r.log.Debugf("Set tombstone key: %s, version: %d, ttl: %v", r.productKey(key), version, ttl)
 }
 
+// DelAllProducts scans and deletes every product key, on a cluster each master is scanned
 func (r *redisRepository) DelAllProducts(ctx context.Context) {
+	defer r.observe("del_all", time.Now())
+
+	pattern := r.productKey("*")
+	var err error
+	if cluster, ok := r.redisClient.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return r.delByPattern(ctx, client, pattern)
+		})
+	} else {
+		err = r.delByPattern(ctx, r.redisClient, pattern)
+	}
+	if err != nil {
+		r.log.WarnMsg("DelAllProducts", err)
+		return
+	}
+
+	// products were cached in a single hash before per product keys
 	if err := r.redisClient.Del(ctx, r.getRedisProductPrefixKey()).Err(); err != nil {
-		r.log.WarnMsg("redisClient.HDel", err)
+		r.log.WarnMsg("redisClient.Del", err)
+	}
+	r.log.Debugf("Del keys: %s", pattern)
+}
+
+func (r *redisRepository) delByPattern(ctx context.Context, client redis.Cmdable, pattern string) error {
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, scanBatchSize).Result()
+		if err != nil {
+			return errors.Wrap(err, "Scan")
+		}
+		for _, key := range keys {
+			// keys of one scan batch may hash to different slots, so they are deleted one by one
+			if err := client.Del(ctx, key).Err(); err != nil {
+				return errors.Wrap(err, "Del")
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// SearchGeneration returns the current search cache generation, cached pages of older generations are never read
+func (r *redisRepository) SearchGeneration(ctx context.Context) (int64, error) {
+	generation, err := r.redisClient.Get(ctx, r.searchGenerationKey()).Int64()
+	if err != nil && err != redis.Nil {
+		return 0, errors.Wrap(err, "redisClient.Get")
+	}
+	return generation, nil
+}
+
+// InvalidateSearches moves to a new search cache generation, called after every projection write
+func (r *redisRepository) InvalidateSearches(ctx context.Context) {
+	defer r.observe("invalidate_searches", time.Now())
+
+	if err := r.redisClient.Incr(ctx, r.searchGenerationKey()).Err(); err != nil {
+		r.log.WarnMsg("redisClient.Incr", err)
+	}
+}
+
+// GetSearch returns ErrCacheMiss when the page is not cached
+func (r *redisRepository) GetSearch(ctx context.Context, generation int64, key string) (*models.ProductsList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.GetSearch")
+	defer span.Finish()
+	defer r.observe("get_search", time.Now())
+
+	listBytes, err := r.redisClient.Get(ctx, r.searchKey(generation, key)).Bytes()
+	if err != nil {
+		r.metrics.SearchCacheMisses.Inc()
+		if err == redis.Nil {
+			return nil, ErrCacheMiss
+		}
+		r.log.WarnMsg("redisClient.Get", err)
+		return nil, errors.Wrap(err, "redisClient.Get")
+	}
+
+	var list models.ProductsList
+	if err := json.Unmarshal(listBytes, &list); err != nil {
+		r.metrics.SearchCacheMisses.Inc()
+		return nil, errors.Wrap(err, "json.Unmarshal")
+	}
+
+	r.metrics.SearchCacheHits.Inc()
+	return &list, nil
+}
+
+// PutSearch caches a result page under the generation read before the page was queried
+func (r *redisRepository) PutSearch(ctx context.Context, generation int64, key string, list *models.ProductsList) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisRepository.PutSearch")
+	defer span.Finish()
+	defer r.observe("put_search", time.Now())
+
+	listBytes, err := json.Marshal(list)
+	if err != nil {
+		r.log.WarnMsg("json.Marshal", err)
 		return
 	}
-	r.log.Debugf("Del key: %s", r.getRedisProductPrefixKey())
+
+	if err := r.redisClient.Set(ctx, r.searchKey(generation, key), listBytes, r.searchTTL()).Err(); err != nil {
+		r.log.WarnMsg("redisClient.Set", err)
+	}
+}
+
+// decodeCachedProduct returns ErrCachedNotFound for negative entries and tombstones
+func decodeCachedProduct(productBytes []byte) (*models.Product, error) {
+	if bytes.Equal(productBytes, notFoundMarker) {
+		return nil, ErrCachedNotFound
+	}
+
+	var tombstone cachedTombstone
+	if err := json.Unmarshal(productBytes, &tombstone); err != nil {
+		return nil, errors.Wrap(err, "json.Unmarshal")
+	}
+	if tombstone.Deleted {
+		return nil, ErrCachedNotFound
+	}
+
+	var product models.Product
+	if err := json.Unmarshal(productBytes, &product); err != nil {
+		return nil, errors.Wrap(err, "json.Unmarshal")
+	}
+	return &product, nil
+}
+
+func (r *redisRepository) observe(operation string, start time.Time) {
+	r.metrics.CacheDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func (r *redisRepository) productKey(key string) string {
+	return fmt.Sprintf("%s:%s", r.getRedisProductPrefixKey(), key)
+}
+
+// productTTL spreads expirations so products cached together do not expire together
+func (r *redisRepository) productTTL() time.Duration {
+	ttl := r.cfg.ServiceSettings.RedisProductTTL
+	if ttl <= 0 {
+		ttl = defaultProductTTL
+	}
+	if jitter := r.cfg.ServiceSettings.RedisProductTTLJitter; jitter > 0 {
+		ttl += time.Duration(rand.Int63n(int64(jitter)))
+	}
+	return ttl
+}
+
+func (r *redisRepository) notFoundTTL() time.Duration {
+	if r.cfg.ServiceSettings.RedisNotFoundTTL > 0 {
+		return r.cfg.ServiceSettings.RedisNotFoundTTL
+	}
+	return defaultNotFoundTTL
+}
+
+func (r *redisRepository) searchGenerationKey() string {
+	return fmt.Sprintf("%s:generation", r.getRedisSearchPrefixKey())
+}
+
+// searchKey hashes the query key so keys stay short whatever the query
+func (r *redisRepository) searchKey(generation int64, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%s:%d:%s", r.getRedisSearchPrefixKey(), generation, hex.EncodeToString(sum[:]))
+}
+
+func (r *redisRepository) searchTTL() time.Duration {
+	if r.cfg.ServiceSettings.RedisSearchTTL > 0 {
+		return r.cfg.ServiceSettings.RedisSearchTTL
+	}
+	return defaultSearchTTL
+}
+
+func (r *redisRepository) getRedisSearchPrefixKey() string {
+	if r.cfg.ServiceSettings.RedisSearchPrefixKey != "" {
+		return r.cfg.ServiceSettings.RedisSearchPrefixKey
+	}
+
+	return redisSearchPrefixKey
 }
 
 func (r *redisRepository) getRedisProductPrefixKey() string {
@@ -3,23 +3,50 @@ package repository
 import (
 	"context"
 
+	"github.com/herhu/Microservices-PR/pkg/search"
 	"github.com/herhu/Microservices-PR/pkg/utils"
 	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
+var (
+	// ErrStaleEvent the stored product is the same or a newer version than the event
+	ErrStaleEvent = errors.New("stale product event")
+)
+
 type Repository interface {
 	CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
 	UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
-	DeleteProduct(ctx context.Context, uuid uuid.UUID) error
+	DeleteProduct(ctx context.Context, uuid uuid.UUID, version int64) error
 
 	GetProductById(ctx context.Context, uuid uuid.UUID) (*models.Product, error)
-	Search(ctx context.Context, search string, pagination *utils.Pagination) (*models.ProductsList, error)
+	GetStoredProduct(ctx context.Context, productID string) (*models.Product, error)
+	GetProductsByIds(ctx context.Context, ids []string) ([]*models.Product, error)
+	Search(ctx context.Context, query *search.Query, pagination *utils.Pagination) (*models.ProductsList, error)
+	SearchAfter(ctx context.Context, query *search.Query, after *search.Cursor, size int) (*models.ProductsList, error)
 }
 
 type CacheRepository interface {
 	PutProduct(ctx context.Context, key string, product *models.Product)
+	PutNotFound(ctx context.Context, key string)
 	GetProduct(ctx context.Context, key string) (*models.Product, error)
-	DelProduct(ctx context.Context, key string)
+	GetProducts(ctx context.Context, keys []string) (map[string]*models.Product, error)
+	PutDeleted(ctx context.Context, key string, version int64)
 	DelAllProducts(ctx context.Context)
+
+	SearchGeneration(ctx context.Context) (int64, error)
+	InvalidateSearches(ctx context.Context)
+	GetSearch(ctx context.Context, generation int64, key string) (*models.ProductsList, error)
+	PutSearch(ctx context.Context, generation int64, key string, list *models.ProductsList)
+}
+
+// ChangeFeed ordered feed of the changes applied to the projection, readable from a resume token.
+// Appended returns a channel closed once changes are appended after the call.
+type ChangeFeed interface {
+	Publish(ctx context.Context, change *models.ProductChange) error
+	Latest(ctx context.Context) (string, error)
+	CheckResumeToken(ctx context.Context, token string) error
+	Read(ctx context.Context, after string, count int64) ([]*models.ProductChange, error)
+	Appended() <-chan struct{}
 }
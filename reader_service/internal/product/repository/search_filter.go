@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/search"
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// searchFilter compiles a parsed query into a mongo filter, free text goes through the text index and
+// field clauses use escaped literal patterns so user input never reaches the regex engine as syntax
+func searchFilter(query *search.Query) bson.D {
+	filter := bson.D{{Key: "deleted", Value: bson.M{"$ne": true}}}
+	and := bson.A{}
+
+	if query.HasText() {
+		filter = append(filter, bson.E{Key: "$text", Value: bson.M{"$search": textSearch(query.Terms)}})
+	} else {
+		for _, term := range query.Terms {
+			pattern := literalPattern(term.Value)
+			and = append(and, bson.M{"$nor": bson.A{
+				bson.M{"name": pattern},
+				bson.M{"description": pattern},
+			}})
+		}
+	}
+
+	for _, field := range query.Fields {
+		pattern := literalPattern(field.Value)
+		if field.Negate {
+			and = append(and, bson.M{field.Field: bson.M{"$not": pattern}})
+			continue
+		}
+		and = append(and, bson.M{field.Field: pattern})
+	}
+
+	for _, price := range query.Prices {
+		bounds := priceBounds(price)
+		if price.Negate {
+			and = append(and, bson.M{search.FieldPrice: bson.M{"$not": bounds}})
+			continue
+		}
+		and = append(and, bson.M{search.FieldPrice: bounds})
+	}
+
+	and = append(and, filterBounds(query.Filter)...)
+
+	if len(and) > 0 {
+		filter = append(filter, bson.E{Key: "$and", Value: and})
+	}
+	return filter
+}
+
+// textSearch builds the $search string, phrases are quoted and negated clauses are prefixed with '-'
+func textSearch(terms []search.Term) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		value := term.Value
+		if term.Phrase {
+			value = `"` + value + `"`
+		}
+		if term.Negate {
+			value = "-" + value
+		}
+		parts = append(parts, value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// filterBounds compiles the price and date filters, open bounds are skipped
+func filterBounds(filter search.Filter) bson.A {
+	bounds := bson.A{}
+
+	price := bson.M{}
+	if filter.MinPrice != nil {
+		price["$gte"] = *filter.MinPrice
+	}
+	if filter.MaxPrice != nil {
+		price["$lte"] = *filter.MaxPrice
+	}
+	if len(price) > 0 {
+		bounds = append(bounds, bson.M{search.FieldPrice: price})
+	}
+
+	dateRanges := []struct {
+		field    string
+		from, to time.Time
+	}{
+		{field: search.SortCreatedAt, from: filter.CreatedFrom, to: filter.CreatedTo},
+		{field: search.SortUpdatedAt, from: filter.UpdatedFrom, to: filter.UpdatedTo},
+	}
+	for _, r := range dateRanges {
+		dates := bson.M{}
+		if !r.from.IsZero() {
+			dates["$gte"] = r.from
+		}
+		if !r.to.IsZero() {
+			dates["$lte"] = r.to
+		}
+		if len(dates) > 0 {
+			bounds = append(bounds, bson.M{r.field: dates})
+		}
+	}
+
+	return bounds
+}
+
+// scoredProduct product with the text score added by the search pipeline
+type scoredProduct struct {
+	models.Product `bson:",inline"`
+	Score          float64 `bson:"score,omitempty"`
+}
+
+// searchPipeline matches the filter and adds the text score when ranking is possible
+func searchPipeline(query *search.Query, filter bson.D) mongo.Pipeline {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if query.HasText() {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+	return pipeline
+}
+
+// sortValue is the sort key of the product stored in a cursor, zero values are omitted in mongo so they are nil
+func sortValue(sort search.Sort, product *models.Product, score float64) interface{} {
+	switch sort.Field {
+	case search.SortName:
+		if product.Name != "" {
+			return product.Name
+		}
+	case search.SortPrice:
+		if product.Price != 0 {
+			return product.Price
+		}
+	case search.SortCreatedAt:
+		if !product.CreatedAt.IsZero() {
+			return product.CreatedAt
+		}
+	case search.SortUpdatedAt:
+		if !product.UpdatedAt.IsZero() {
+			return product.UpdatedAt
+		}
+	case search.SortRelevance:
+		return score
+	}
+	return nil
+}
+
+// searchSort defaults to relevance for text searches, _id breaks ties so pages stay stable
+func searchSort(query *search.Query) bson.D {
+	sort := query.ResolvedSort()
+	if sort.IsZero() {
+		return nil
+	}
+	return bson.D{{Key: sortKey(sort), Value: sortDirection(sort)}, {Key: "_id", Value: 1}}
+}
+
+// cursorSort is searchSort ordering by _id when no sort field applies, cursors always need a total order
+func cursorSort(query *search.Query) bson.D {
+	if sort := searchSort(query); sort != nil {
+		return sort
+	}
+	return bson.D{{Key: "_id", Value: 1}}
+}
+
+// cursorFilter matches the products after the cursor in the order of cursorSort, missing sort fields sort
+// before any value so they come first ascending and last descending
+func cursorFilter(cursor *search.Cursor) bson.M {
+	after := bson.M{"_id": bson.M{"$gt": cursor.ID}}
+	if cursor.Sort == "" {
+		return after
+	}
+
+	key := sortKey(search.Sort{Field: cursor.Sort})
+	tie := bson.M{key: cursor.Value, "_id": bson.M{"$gt": cursor.ID}}
+
+	switch {
+	case cursor.Value == nil && cursor.Desc:
+		return bson.M{key: nil, "_id": bson.M{"$gt": cursor.ID}}
+	case cursor.Value == nil:
+		return bson.M{"$or": bson.A{bson.M{key: bson.M{"$ne": nil}}, tie}}
+	case cursor.Desc:
+		return bson.M{"$or": bson.A{bson.M{key: bson.M{"$lt": cursor.Value}}, tie, bson.M{key: nil}}}
+	default:
+		return bson.M{"$or": bson.A{bson.M{key: bson.M{"$gt": cursor.Value}}, tie}}
+	}
+}
+
+func sortKey(sort search.Sort) string {
+	if sort.Field == search.SortRelevance {
+		return "score"
+	}
+	return sort.Field
+}
+
+func sortDirection(sort search.Sort) int {
+	if sort.Desc {
+		return -1
+	}
+	return 1
+}
+
+func literalPattern(value string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}
+}
+
+func priceBounds(price search.PriceRange) bson.M {
+	bounds := bson.M{}
+	if price.Min != nil {
+		if price.MinExclusive {
+			bounds["$gt"] = *price.Min
+		} else {
+			bounds["$gte"] = *price.Min
+		}
+	}
+	if price.Max != nil {
+		if price.MaxExclusive {
+			bounds["$lt"] = *price.Max
+		} else {
+			bounds["$lte"] = *price.Max
+		}
+	}
+	return bounds
+}
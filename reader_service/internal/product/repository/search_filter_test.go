@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/herhu/Microservices-PR/pkg/search"
+	"github.com/herhu/Microservices-PR/reader_service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorFilter(t *testing.T) {
+	afterID := bson.M{"_id": bson.M{"$gt": "p1"}}
+
+	tests := []struct {
+		name   string
+		cursor *search.Cursor
+		want   bson.M
+	}{
+		{
+			name:   "id only",
+			cursor: &search.Cursor{ID: "p1"},
+			want:   afterID,
+		},
+		{
+			name:   "ascending value",
+			cursor: &search.Cursor{Sort: search.SortPrice, Value: 9.5, ID: "p1"},
+			want: bson.M{"$or": bson.A{
+				bson.M{"price": bson.M{"$gt": 9.5}},
+				bson.M{"price": 9.5, "_id": bson.M{"$gt": "p1"}},
+			}},
+		},
+		{
+			name:   "descending value continues with missing values",
+			cursor: &search.Cursor{Sort: search.SortPrice, Desc: true, Value: 9.5, ID: "p1"},
+			want: bson.M{"$or": bson.A{
+				bson.M{"price": bson.M{"$lt": 9.5}},
+				bson.M{"price": 9.5, "_id": bson.M{"$gt": "p1"}},
+				bson.M{"price": nil},
+			}},
+		},
+		{
+			name:   "ascending nil value continues with every value",
+			cursor: &search.Cursor{Sort: search.SortName, ID: "p1"},
+			want: bson.M{"$or": bson.A{
+				bson.M{"name": bson.M{"$ne": nil}},
+				bson.M{"name": nil, "_id": bson.M{"$gt": "p1"}},
+			}},
+		},
+		{
+			name:   "descending nil value continues with missing values only",
+			cursor: &search.Cursor{Sort: search.SortName, Desc: true, ID: "p1"},
+			want:   bson.M{"name": nil, "_id": bson.M{"$gt": "p1"}},
+		},
+		{
+			name:   "relevance uses the score",
+			cursor: &search.Cursor{Sort: search.SortRelevance, Desc: true, Value: 1.5, ID: "p1"},
+			want: bson.M{"$or": bson.A{
+				bson.M{"score": bson.M{"$lt": 1.5}},
+				bson.M{"score": 1.5, "_id": bson.M{"$gt": "p1"}},
+				bson.M{"score": nil},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cursorFilter(tt.cursor); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("cursorFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilter(t *testing.T) {
+	notDeleted := bson.E{Key: "deleted", Value: bson.M{"$ne": true}}
+	pattern := func(value string) primitive.Regex {
+		return primitive.Regex{Pattern: value, Options: "i"}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  bson.D
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  bson.D{notDeleted},
+		},
+		{
+			name:  "text with phrase and negation",
+			input: `shoes "running fast" -red`,
+			want: bson.D{
+				notDeleted,
+				{Key: "$text", Value: bson.M{"$search": `shoes "running fast" -red`}},
+			},
+		},
+		{
+			name:  "negated terms without text",
+			input: "-red",
+			want: bson.D{
+				notDeleted,
+				{Key: "$and", Value: bson.A{bson.M{"$nor": bson.A{
+					bson.M{"name": pattern("red")},
+					bson.M{"description": pattern("red")},
+				}}}},
+			},
+		},
+		{
+			name:  "escaped and negated fields",
+			input: `name:a.b -description:"c*"`,
+			want: bson.D{
+				notDeleted,
+				{Key: "$and", Value: bson.A{
+					bson.M{"name": pattern(`a\.b`)},
+					bson.M{"description": bson.M{"$not": pattern(`c\*`)}},
+				}},
+			},
+		},
+		{
+			name:  "exclusive and negated prices",
+			input: "price:>10 -price:<=2",
+			want: bson.D{
+				notDeleted,
+				{Key: "$and", Value: bson.A{
+					bson.M{"price": bson.M{"$gt": 10.0}},
+					bson.M{"price": bson.M{"$not": bson.M{"$lte": 2.0}}},
+				}},
+			},
+		},
+		{
+			name:  "exclusive price range bounds",
+			input: "price:>1 price:<5",
+			want: bson.D{
+				notDeleted,
+				{Key: "$and", Value: bson.A{
+					bson.M{"price": bson.M{"$gt": 1.0}},
+					bson.M{"price": bson.M{"$lt": 5.0}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := search.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if got := searchFilter(query); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("searchFilter(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortValue(t *testing.T) {
+	tests := []struct {
+		name string
+		sort search.Sort
+		want interface{}
+	}{
+		{name: "missing name is nil", sort: search.Sort{Field: search.SortName}, want: nil},
+		{name: "zero price is nil", sort: search.Sort{Field: search.SortPrice}, want: nil},
+		{name: "relevance is the score", sort: search.Sort{Field: search.SortRelevance, Desc: true}, want: 1.5},
+		{name: "no sort is nil", sort: search.Sort{}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortValue(tt.sort, &models.Product{}, 1.5); got != tt.want {
+				t.Fatalf("sortValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,17 +18,20 @@ func NewProductService(
 	cfg *config.Config,
 	mongoRepo repository.Repository,
 	redisRepo repository.CacheRepository,
+	changeFeed repository.ChangeFeed,
 ) *ProductService {
 
-	createProductHandler := commands.NewCreateProductHandler(log, cfg, mongoRepo, redisRepo)
-	deleteProductCmdHandler := commands.NewDeleteProductCmdHandler(log, cfg, mongoRepo, redisRepo)
-	updateProductCmdHandler := commands.NewUpdateProductCmdHandler(log, cfg, mongoRepo, redisRepo)
+	createProductHandler := commands.NewCreateProductHandler(log, cfg, mongoRepo, redisRepo, changeFeed)
+	deleteProductCmdHandler := commands.NewDeleteProductCmdHandler(log, cfg, mongoRepo, redisRepo, changeFeed)
+	updateProductCmdHandler := commands.NewUpdateProductCmdHandler(log, cfg, mongoRepo, redisRepo, changeFeed)
 
 	getProductByIdHandler := queries.NewGetProductByIdHandler(log, cfg, mongoRepo, redisRepo)
+	getProductsByIdsHandler := queries.NewGetProductsByIdsHandler(log, cfg, mongoRepo, redisRepo)
 	searchProductHandler := queries.NewSearchProductHandler(log, cfg, mongoRepo, redisRepo)
+	watchProductsHandler := queries.NewWatchProductsHandler(log, cfg, changeFeed)
 
 	productCommands := commands.NewProductCommands(createProductHandler, updateProductCmdHandler, deleteProductCmdHandler)
-	productQueries := queries.NewProductQueries(getProductByIdHandler, searchProductHandler)
+	productQueries := queries.NewProductQueries(getProductByIdHandler, getProductsByIdsHandler, searchProductHandler, watchProductsHandler)
 
 	return &ProductService{Commands: productCommands, Queries: productQueries}
 }
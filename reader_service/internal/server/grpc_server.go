@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"net"
 	"time"
 
@@ -24,7 +25,7 @@ const (
 	gRPCTime          = 10
 )
 
-func (s *server) newReaderGrpcServer() (func() error, *grpc.Server, error) {
+func (s *server) newReaderGrpcServer(ctx context.Context) (func() error, *grpc.Server, error) {
 	l, err := net.Listen("tcp", s.cfg.GRPC.Port)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "net.Listen")
@@ -45,6 +46,14 @@ func (s *server) newReaderGrpcServer() (func() error, *grpc.Server, error) {
 			s.im.Logger,
 		),
 		),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+			grpc_ctxtags.StreamServerInterceptor(),
+			grpc_opentracing.StreamServerInterceptor(),
+			grpc_prometheus.StreamServerInterceptor,
+			grpc_recovery.StreamServerInterceptor(),
+			shutdownStreamInterceptor(ctx),
+		),
+		),
 	)
 
 	readerGrpcService := readerGrpc.NewReaderGrpcService(s.log, s.cfg, s.v, s.ps, s.metrics)
@@ -62,3 +71,23 @@ func (s *server) newReaderGrpcServer() (func() error, *grpc.Server, error) {
 
 	return l.Close, grpcServer, nil
 }
+
+// shutdownStreamInterceptor ends long lived streams when the service shuts down, GracefulStop waits for them otherwise
+func shutdownStreamInterceptor(shutdown context.Context) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithCancel(stream.Context())
+		defer cancel()
+
+		go func() {
+			select {
+			case <-shutdown.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
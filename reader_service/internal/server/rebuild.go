@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/herhu/Microservices-PR/pkg/interceptors"
+	"github.com/herhu/Microservices-PR/pkg/mongodb"
+	redisClient "github.com/herhu/Microservices-PR/pkg/redis"
+	"github.com/herhu/Microservices-PR/reader_service/internal/client"
+	"github.com/herhu/Microservices-PR/reader_service/internal/metrics"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/rebuild"
+	"github.com/herhu/Microservices-PR/reader_service/internal/product/repository"
+	writerService "github.com/herhu/Microservices-PR/writer_service/proto/product_writer"
+	"github.com/pkg/errors"
+)
+
+// RunRebuild rebuilds the products projection and returns, the running reader services keep consuming meanwhile
+func (s *server) RunRebuild(opts rebuild.Options) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	s.im = interceptors.NewInterceptorManager(s.log)
+	s.metrics = metrics.NewReaderServiceMetrics(s.cfg)
+
+	mongoDBConn, err := mongodb.NewMongoDBConn(ctx, s.cfg.Mongo)
+	if err != nil {
+		return errors.Wrap(err, "NewMongoDBConn")
+	}
+	s.mongoClient = mongoDBConn
+	defer mongoDBConn.Disconnect(ctx) // nolint: errcheck
+
+	s.redisClient = redisClient.NewUniversalRedisClient(s.cfg.Redis)
+	defer s.redisClient.Close() // nolint: errcheck
+
+	if err := s.connectKafkaBrokers(ctx); err != nil {
+		return errors.Wrap(err, "s.connectKafkaBrokers")
+	}
+	defer s.broker.Close() // nolint: errcheck
+
+	var writerClient writerService.WriterServiceClient
+	if opts.Source == rebuild.SourceWriter {
+		writerServiceConn, err := client.NewWriterServiceConn(ctx, s.cfg, s.im)
+		if err != nil {
+			return err
+		}
+		defer writerServiceConn.Close() // nolint: errcheck
+		writerClient = writerService.NewWriterServiceClient(writerServiceConn)
+	}
+
+	redisRepo := repository.NewRedisRepository(s.log, s.cfg, s.redisClient, s.metrics)
+	rebuilder := rebuild.NewRebuilder(s.log, s.cfg, s.mongoClient, redisRepo, s.broker, writerClient)
+
+	s.log.Infof("Rebuilding products projection, source: %s, from: %v", opts.Source, opts.From)
+	if err := rebuilder.Run(ctx, opts); err != nil {
+		return errors.Wrap(err, "rebuilder.Run")
+	}
+
+	s.log.Info("Products projection rebuilt")
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/go-playground/validator"
@@ -21,20 +22,20 @@ import (
 	"github.com/herhu/Microservices-PR/reader_service/internal/product/service"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
-	"github.com/segmentio/kafka-go"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type server struct {
-	log         logger.Logger
-	cfg         *config.Config
-	v           *validator.Validate
-	kafkaConn   *kafka.Conn
-	im          interceptors.InterceptorManager
-	mongoClient *mongo.Client
-	redisClient redis.UniversalClient
-	ps          *service.ProductService
-	metrics     *metrics.ReaderServiceMetrics
+	log           logger.Logger
+	cfg           *config.Config
+	v             *validator.Validate
+	broker        kafkaClient.Broker
+	consumerGroup kafkaClient.ConsumerGroup
+	im            interceptors.InterceptorManager
+	mongoClient   *mongo.Client
+	redisClient   redis.UniversalClient
+	ps            *service.ProductService
+	metrics       *metrics.ReaderServiceMetrics
 }
 
 func NewServer(log logger.Logger, cfg *config.Config) *server {
@@ -61,20 +62,44 @@ func (s *server) Run() error {
 	s.log.Infof("Redis connected: %+v", s.redisClient.PoolStats())
 
 	mongoRepo := repository.NewMongoRepository(s.log, s.cfg, s.mongoClient)
-	redisRepo := repository.NewRedisRepository(s.log, s.cfg, s.redisClient)
-
-	s.ps = service.NewProductService(s.log, s.cfg, mongoRepo, redisRepo)
+	if err := mongoRepo.EnsureIndexes(ctx); err != nil {
+		return errors.Wrap(err, "mongoRepo.EnsureIndexes")
+	}
+	redisRepo := repository.NewRedisRepository(s.log, s.cfg, s.redisClient, s.metrics)
 
-	readerMessageProcessor := readerKafka.NewReaderMessageProcessor(s.log, s.cfg, s.v, s.ps, s.metrics)
+	changeFeed := repository.NewRedisChangeFeed(s.log, s.cfg, s.redisClient)
+	go changeFeed.Run(ctx)
 
-	s.log.Info("Starting Reader Kafka consumers")
-	cg := kafkaClient.NewConsumerGroup(s.cfg.Kafka.Brokers, s.cfg.Kafka.GroupID, s.log)
-	go cg.ConsumeTopic(ctx, s.getConsumerGroupTopics(), readerKafka.PoolSize, readerMessageProcessor.ProcessMessages)
+	s.ps = service.NewProductService(s.log, s.cfg, mongoRepo, redisRepo, changeFeed)
 
 	if err := s.connectKafkaBrokers(ctx); err != nil {
 		return errors.Wrap(err, "s.connectKafkaBrokers")
 	}
-	defer s.kafkaConn.Close() // nolint: errcheck
+	defer s.broker.Close() // nolint: errcheck
+
+	if s.cfg.Kafka.InitTopics {
+		s.initKafkaTopics(ctx)
+	}
+
+	kafkaProducer := s.broker.NewProducer()
+	defer kafkaProducer.Close() // nolint: errcheck
+
+	readerMessageProcessor := readerKafka.NewReaderMessageProcessor(s.log, s.cfg, s.v, s.ps, s.metrics, kafkaProducer)
+
+	s.log.Info("Starting Reader Kafka consumers")
+	s.consumerGroup = kafkaClient.NewConsumerGroup(s.broker, s.cfg.Kafka, s.log)
+	retryRedeliverer := kafkaClient.NewRetryRedeliverer(s.log, s.cfg.Kafka, s.broker, s.getConsumerGroupTopics(), kafkaProducer)
+
+	consumersWg := &sync.WaitGroup{}
+	consumersWg.Add(2)
+	go func() {
+		defer consumersWg.Done()
+		s.consumerGroup.ConsumeTopic(ctx, s.getConsumerGroupTopics(), readerKafka.PoolSize, readerMessageProcessor.ProcessMessage)
+	}()
+	go func() {
+		defer consumersWg.Done()
+		retryRedeliverer.Run(ctx)
+	}()
 
 	s.runHealthCheck(ctx)
 	s.runMetrics(cancel)
@@ -88,7 +113,7 @@ func (s *server) Run() error {
 		opentracing.SetGlobalTracer(tracer)
 	}
 
-	closeGrpcServer, grpcServer, err := s.newReaderGrpcServer()
+	closeGrpcServer, grpcServer, err := s.newReaderGrpcServer(ctx)
 	if err != nil {
 		return errors.Wrap(err, "NewScmGrpcServer")
 	}
@@ -96,5 +121,8 @@ func (s *server) Run() error {
 
 	<-ctx.Done()
 	grpcServer.GracefulStop()
+
+	s.log.Info("Waiting for Kafka consumers to drain")
+	consumersWg.Wait()
 	return nil
 }
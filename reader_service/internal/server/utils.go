@@ -12,6 +12,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/segmentio/kafka-go"
 )
 
 const (
@@ -19,23 +20,55 @@ const (
 )
 
 func (s *server) connectKafkaBrokers(ctx context.Context) error {
-	kafkaConn, err := kafkaClient.NewKafkaConn(ctx, s.cfg.Kafka)
+	broker, err := kafkaClient.NewBroker(s.log, s.cfg.Kafka)
 	if err != nil {
-		return errors.Wrap(err, "kafka.NewKafkaCon")
+		return errors.Wrap(err, "kafka.NewBroker")
 	}
 
-	s.kafkaConn = kafkaConn
-
-	brokers, err := kafkaConn.Brokers()
-	if err != nil {
-		return errors.Wrap(err, "kafkaConn.Brokers")
+	if err := broker.Ping(ctx); err != nil {
+		return errors.Wrap(err, "broker.Ping")
 	}
 
-	s.log.Infof("kafka connected to brokers: %+v", brokers)
+	s.broker = broker
+	s.log.Infof("kafka connected, driver: %s, brokers: %+v", s.cfg.Kafka.Driver, s.cfg.Kafka.Brokers)
 
 	return nil
 }
 
+func (s *server) initKafkaTopics(ctx context.Context) {
+	productCreatedDLQTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.ProductCreatedDLQ.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.ProductCreatedDLQ.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.ProductCreatedDLQ.ReplicationFactor,
+	}
+
+	productUpdatedDLQTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.ProductUpdatedDLQ.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.ProductUpdatedDLQ.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.ProductUpdatedDLQ.ReplicationFactor,
+	}
+
+	productDeletedDLQTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.ProductDeletedDLQ.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.ProductDeletedDLQ.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.ProductDeletedDLQ.ReplicationFactor,
+	}
+
+	topics := []kafka.TopicConfig{
+		productCreatedDLQTopic,
+		productUpdatedDLQTopic,
+		productDeletedDLQTopic,
+	}
+	topics = append(topics, s.cfg.Kafka.Retry.TopicConfigs(s.getConsumerGroupTopics())...)
+
+	if err := s.broker.CreateTopics(ctx, topics...); err != nil {
+		s.log.WarnMsg("broker.CreateTopics", err)
+		return
+	}
+
+	s.log.Infof("kafka topics created or already exists: %+v", topics)
+}
+
 func (s *server) getConsumerGroupTopics() []string {
 	return []string{
 		s.cfg.KafkaTopics.ProductCreated.TopicName,
@@ -60,13 +93,11 @@ func (s *server) runHealthCheck(ctx context.Context) {
 	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
 
 	health.AddReadinessCheck(constants.Kafka, healthcheck.AsyncWithContext(ctx, func() error {
-		_, err := s.kafkaConn.Brokers()
-		if err != nil {
-			return err
-		}
-		return nil
+		return s.broker.Ping(ctx)
 	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
 
+	health.AddReadinessCheck(constants.KafkaConsumer, s.consumerGroup.Ready)
+
 	go func() {
 		s.log.Infof("Reader microservice Kubernetes probes listening on port: %s", s.cfg.Probes.Port)
 		if err := http.ListenAndServe(s.cfg.Probes.Port, health); err != nil {
@@ -26,7 +26,7 @@ var file_product_reader_proto_rawDesc = []byte{
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65,
 	0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1d, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x5f, 0x72,
 	0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x32, 0xaf, 0x03, 0x0a, 0x0d, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53,
+	0x72, 0x6f, 0x74, 0x6f, 0x32, 0xdd, 0x04, 0x0a, 0x0d, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53,
 	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
 	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12, 0x1f, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72,
 	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72,
@@ -43,49 +43,67 @@ var file_product_reader_proto_rawDesc = []byte{
 	0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71,
 	0x1a, 0x20, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
 	0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52,
-	0x65, 0x73, 0x12, 0x43, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x64,
-	0x75, 0x63, 0x74, 0x12, 0x18, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e,
-	0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65,
-	0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x12, 0x5d, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x23, 0x2e, 0x72,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65,
-	0x71, 0x1a, 0x23, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42,
-	0x79, 0x49, 0x64, 0x52, 0x65, 0x73, 0x42, 0x12, 0x5a, 0x10, 0x2e, 0x2f, 0x3b, 0x72, 0x65, 0x61,
-	0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x65, 0x73, 0x12, 0x5a, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x12, 0x22, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x22, 0x2e, 0x72, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x52, 0x65, 0x73, 0x12, 0x43,
+	0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12,
+	0x18, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x72, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x12, 0x5d, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x23, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e,
+	0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52,
+	0x65, 0x73, 0x12, 0x50, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x1a, 0x1c, 0x2e, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x30, 0x01, 0x42, 0x12, 0x5a, 0x10, 0x2e, 0x2f, 0x3b, 0x72, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_product_reader_proto_goTypes = []interface{}{
 	(*CreateProductReq)(nil),     // 0: readerService.CreateProductReq
 	(*UpdateProductReq)(nil),     // 1: readerService.UpdateProductReq
 	(*GetProductByIdReq)(nil),    // 2: readerService.GetProductByIdReq
-	(*SearchReq)(nil),            // 3: readerService.SearchReq
-	(*DeleteProductByIdReq)(nil), // 4: readerService.DeleteProductByIdReq
-	(*CreateProductRes)(nil),     // 5: readerService.CreateProductRes
-	(*UpdateProductRes)(nil),     // 6: readerService.UpdateProductRes
-	(*GetProductByIdRes)(nil),    // 7: readerService.GetProductByIdRes
-	(*SearchRes)(nil),            // 8: readerService.SearchRes
-	(*DeleteProductByIdRes)(nil), // 9: readerService.DeleteProductByIdRes
+	(*GetProductsByIdsReq)(nil),  // 3: readerService.GetProductsByIdsReq
+	(*SearchReq)(nil),            // 4: readerService.SearchReq
+	(*DeleteProductByIdReq)(nil), // 5: readerService.DeleteProductByIdReq
+	(*WatchProductsReq)(nil),     // 6: readerService.WatchProductsReq
+	(*CreateProductRes)(nil),     // 7: readerService.CreateProductRes
+	(*UpdateProductRes)(nil),     // 8: readerService.UpdateProductRes
+	(*GetProductByIdRes)(nil),    // 9: readerService.GetProductByIdRes
+	(*GetProductsByIdsRes)(nil),  // 10: readerService.GetProductsByIdsRes
+	(*SearchRes)(nil),            // 11: readerService.SearchRes
+	(*DeleteProductByIdRes)(nil), // 12: readerService.DeleteProductByIdRes
+	(*ProductChange)(nil),        // 13: readerService.ProductChange
 }
 var file_product_reader_proto_depIdxs = []int32{
-	0, // 0: readerService.readerService.CreateProduct:input_type -> readerService.CreateProductReq
-	1, // 1: readerService.readerService.UpdateProduct:input_type -> readerService.UpdateProductReq
-	2, // 2: readerService.readerService.GetProductById:input_type -> readerService.GetProductByIdReq
-	3, // 3: readerService.readerService.SearchProduct:input_type -> readerService.SearchReq
-	4, // 4: readerService.readerService.DeleteProductByID:input_type -> readerService.DeleteProductByIdReq
-	5, // 5: readerService.readerService.CreateProduct:output_type -> readerService.CreateProductRes
-	6, // 6: readerService.readerService.UpdateProduct:output_type -> readerService.UpdateProductRes
-	7, // 7: readerService.readerService.GetProductById:output_type -> readerService.GetProductByIdRes
-	8, // 8: readerService.readerService.SearchProduct:output_type -> readerService.SearchRes
-	9, // 9: readerService.readerService.DeleteProductByID:output_type -> readerService.DeleteProductByIdRes
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: readerService.readerService.CreateProduct:input_type -> readerService.CreateProductReq
+	1,  // 1: readerService.readerService.UpdateProduct:input_type -> readerService.UpdateProductReq
+	2,  // 2: readerService.readerService.GetProductById:input_type -> readerService.GetProductByIdReq
+	3,  // 3: readerService.readerService.GetProductsByIds:input_type -> readerService.GetProductsByIdsReq
+	4,  // 4: readerService.readerService.SearchProduct:input_type -> readerService.SearchReq
+	5,  // 5: readerService.readerService.DeleteProductByID:input_type -> readerService.DeleteProductByIdReq
+	6,  // 6: readerService.readerService.WatchProducts:input_type -> readerService.WatchProductsReq
+	7,  // 7: readerService.readerService.CreateProduct:output_type -> readerService.CreateProductRes
+	8,  // 8: readerService.readerService.UpdateProduct:output_type -> readerService.UpdateProductRes
+	9,  // 9: readerService.readerService.GetProductById:output_type -> readerService.GetProductByIdRes
+	10, // 10: readerService.readerService.GetProductsByIds:output_type -> readerService.GetProductsByIdsRes
+	11, // 11: readerService.readerService.SearchProduct:output_type -> readerService.SearchRes
+	12, // 12: readerService.readerService.DeleteProductByID:output_type -> readerService.DeleteProductByIdRes
+	13, // 13: readerService.readerService.WatchProducts:output_type -> readerService.ProductChange
+	7,  // [7:14] is the sub-list for method output_type
+	0,  // [0:7] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_product_reader_proto_init() }
@@ -20,8 +20,10 @@ type ReaderServiceClient interface {
 	CreateProduct(ctx context.Context, in *CreateProductReq, opts ...grpc.CallOption) (*CreateProductRes, error)
 	UpdateProduct(ctx context.Context, in *UpdateProductReq, opts ...grpc.CallOption) (*UpdateProductRes, error)
 	GetProductById(ctx context.Context, in *GetProductByIdReq, opts ...grpc.CallOption) (*GetProductByIdRes, error)
+	GetProductsByIds(ctx context.Context, in *GetProductsByIdsReq, opts ...grpc.CallOption) (*GetProductsByIdsRes, error)
 	SearchProduct(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (*SearchRes, error)
 	DeleteProductByID(ctx context.Context, in *DeleteProductByIdReq, opts ...grpc.CallOption) (*DeleteProductByIdRes, error)
+	WatchProducts(ctx context.Context, in *WatchProductsReq, opts ...grpc.CallOption) (ReaderService_WatchProductsClient, error)
 }
 
 type readerServiceClient struct {
@@ -59,6 +61,15 @@ func (c *readerServiceClient) GetProductById(ctx context.Context, in *GetProduct
 	return out, nil
 }
 
+func (c *readerServiceClient) GetProductsByIds(ctx context.Context, in *GetProductsByIdsReq, opts ...grpc.CallOption) (*GetProductsByIdsRes, error) {
+	out := new(GetProductsByIdsRes)
+	err := c.cc.Invoke(ctx, "/readerService.readerService/GetProductsByIds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *readerServiceClient) SearchProduct(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (*SearchRes, error) {
 	out := new(SearchRes)
 	err := c.cc.Invoke(ctx, "/readerService.readerService/SearchProduct", in, out, opts...)
@@ -77,6 +88,38 @@ func (c *readerServiceClient) DeleteProductByID(ctx context.Context, in *DeleteP
 	return out, nil
 }
 
+func (c *readerServiceClient) WatchProducts(ctx context.Context, in *WatchProductsReq, opts ...grpc.CallOption) (ReaderService_WatchProductsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ReaderService_serviceDesc.Streams[0], "/readerService.readerService/WatchProducts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &readerServiceWatchProductsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReaderService_WatchProductsClient interface {
+	Recv() (*ProductChange, error)
+	grpc.ClientStream
+}
+
+type readerServiceWatchProductsClient struct {
+	grpc.ClientStream
+}
+
+func (x *readerServiceWatchProductsClient) Recv() (*ProductChange, error) {
+	m := new(ProductChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ReaderServiceServer is the server API for ReaderService service.
 // All implementations should embed UnimplementedReaderServiceServer
 // for forward compatibility
@@ -84,8 +127,10 @@ type ReaderServiceServer interface {
 	CreateProduct(context.Context, *CreateProductReq) (*CreateProductRes, error)
 	UpdateProduct(context.Context, *UpdateProductReq) (*UpdateProductRes, error)
 	GetProductById(context.Context, *GetProductByIdReq) (*GetProductByIdRes, error)
+	GetProductsByIds(context.Context, *GetProductsByIdsReq) (*GetProductsByIdsRes, error)
 	SearchProduct(context.Context, *SearchReq) (*SearchRes, error)
 	DeleteProductByID(context.Context, *DeleteProductByIdReq) (*DeleteProductByIdRes, error)
+	WatchProducts(*WatchProductsReq, ReaderService_WatchProductsServer) error
 }
 
 // UnimplementedReaderServiceServer should be embedded to have forward compatible implementations.
@@ -101,12 +146,18 @@ func (UnimplementedReaderServiceServer) UpdateProduct(context.Context, *UpdatePr
 func (UnimplementedReaderServiceServer) GetProductById(context.Context, *GetProductByIdReq) (*GetProductByIdRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetProductById not implemented")
 }
+func (UnimplementedReaderServiceServer) GetProductsByIds(context.Context, *GetProductsByIdsReq) (*GetProductsByIdsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductsByIds not implemented")
+}
 func (UnimplementedReaderServiceServer) SearchProduct(context.Context, *SearchReq) (*SearchRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchProduct not implemented")
 }
 func (UnimplementedReaderServiceServer) DeleteProductByID(context.Context, *DeleteProductByIdReq) (*DeleteProductByIdRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteProductByID not implemented")
 }
+func (UnimplementedReaderServiceServer) WatchProducts(*WatchProductsReq, ReaderService_WatchProductsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProducts not implemented")
+}
 
 // UnsafeReaderServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ReaderServiceServer will
@@ -173,6 +224,24 @@ func _ReaderService_GetProductById_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ReaderService_GetProductsByIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductsByIdsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReaderServiceServer).GetProductsByIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/readerService.readerService/GetProductsByIds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReaderServiceServer).GetProductsByIds(ctx, req.(*GetProductsByIdsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ReaderService_SearchProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SearchReq)
 	if err := dec(in); err != nil {
@@ -209,6 +278,27 @@ func _ReaderService_DeleteProductByID_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ReaderService_WatchProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProductsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReaderServiceServer).WatchProducts(m, &readerServiceWatchProductsServer{stream})
+}
+
+type ReaderService_WatchProductsServer interface {
+	Send(*ProductChange) error
+	grpc.ServerStream
+}
+
+type readerServiceWatchProductsServer struct {
+	grpc.ServerStream
+}
+
+func (x *readerServiceWatchProductsServer) Send(m *ProductChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _ReaderService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "readerService.readerService",
 	HandlerType: (*ReaderServiceServer)(nil),
@@ -225,6 +315,10 @@ var _ReaderService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetProductById",
 			Handler:    _ReaderService_GetProductById_Handler,
 		},
+		{
+			MethodName: "GetProductsByIds",
+			Handler:    _ReaderService_GetProductsByIds_Handler,
+		},
 		{
 			MethodName: "SearchProduct",
 			Handler:    _ReaderService_SearchProduct_Handler,
@@ -234,6 +328,12 @@ var _ReaderService_serviceDesc = grpc.ServiceDesc{
 			Handler:    _ReaderService_DeleteProductByID_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchProducts",
+			Handler:       _ReaderService_WatchProducts_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "product_reader.proto",
 }
@@ -9,10 +9,14 @@ import (
 	"github.com/herhu/Microservices-PR/writer_service/internal/server"
 )
 
+var (
+	configPath = flag.String("config", "", "Writer microservice config path")
+)
+
 func main() {
 	flag.Parse()
 
-	cfg, err := config.InitConfig()
+	cfg, err := config.InitConfig(*configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"flag"
 	"fmt"
 	"os"
 
@@ -11,16 +10,11 @@ import (
 	"github.com/herhu/Microservices-PR/pkg/postgres"
 	"github.com/herhu/Microservices-PR/pkg/probes"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
-var configPath string
-
-func init() {
-	flag.StringVar(&configPath, "config", "", "Writer microservice microservice config path")
-}
-
 type Config struct {
 	ServiceName string              `mapstructure:"serviceName"`
 	Logger      *logger.Config      `mapstructure:"logger"`
@@ -30,6 +24,8 @@ type Config struct {
 	Kafka       *kafkaClient.Config `mapstructure:"kafka"`
 	Probes      probes.Config       `mapstructure:"probes"`
 	Jaeger      *tracing.Config     `mapstructure:"jaeger"`
+	OutboxRelay OutboxRelay         `mapstructure:"outboxRelay"`
+	EventStore  EventStore          `mapstructure:"eventStore"`
 }
 
 type GRPC struct {
@@ -37,6 +33,16 @@ type GRPC struct {
 	Development bool   `mapstructure:"development"`
 }
 
+type OutboxRelay struct {
+	PollIntervalMs int `mapstructure:"pollIntervalMs"`
+	BatchSize      int `mapstructure:"batchSize"`
+	RetentionHours int `mapstructure:"retentionHours"`
+}
+
+type EventStore struct {
+	SnapshotFrequency int `mapstructure:"snapshotFrequency"`
+}
+
 type KafkaTopics struct {
 	ProductCreate  kafkaClient.TopicConfig `mapstructure:"productCreate"`
 	ProductCreated kafkaClient.TopicConfig `mapstructure:"productCreated"`
@@ -44,9 +50,25 @@ type KafkaTopics struct {
 	ProductUpdated kafkaClient.TopicConfig `mapstructure:"productUpdated"`
 	ProductDelete  kafkaClient.TopicConfig `mapstructure:"productDelete"`
 	ProductDeleted kafkaClient.TopicConfig `mapstructure:"productDeleted"`
+
+	ProductCreateDLQ kafkaClient.TopicConfig `mapstructure:"productCreateDLQ"`
+	ProductUpdateDLQ kafkaClient.TopicConfig `mapstructure:"productUpdateDLQ"`
+	ProductDeleteDLQ kafkaClient.TopicConfig `mapstructure:"productDeleteDLQ"`
+
+	CommandResult kafkaClient.TopicConfig `mapstructure:"commandResult"`
+}
+
+// Routes consumed topic routes for legacy messages and dead lettering
+func (t KafkaTopics) Routes() kafkaClient.TopicRoutes {
+	return kafkaClient.TopicRoutes{
+		t.ProductCreate.TopicName: {EventType: kafkaMessages.ProductCreateType, DeadLetterTopic: t.ProductCreateDLQ.TopicName},
+		t.ProductUpdate.TopicName: {EventType: kafkaMessages.ProductUpdateType, DeadLetterTopic: t.ProductUpdateDLQ.TopicName},
+		t.ProductDelete.TopicName: {EventType: kafkaMessages.ProductDeleteType, DeadLetterTopic: t.ProductDeleteDLQ.TopicName},
+	}
 }
 
-func InitConfig() (*Config, error) {
+// InitConfig loads the config file, an empty path falls back to the config path env and the default config
+func InitConfig(configPath string) (*Config, error) {
 	if configPath == "" {
 		configPathFromEnv := os.Getenv(constants.ConfigPath)
 		if configPathFromEnv != "" {
@@ -94,6 +116,10 @@ func InitConfig() (*Config, error) {
 	if kafkaBrokers != "" {
 		cfg.Kafka.Brokers = []string{kafkaBrokers}
 	}
+	kafkaDriver := os.Getenv(constants.KafkaDriver)
+	if kafkaDriver != "" {
+		cfg.Kafka.Driver = kafkaDriver
+	}
 
 	return cfg, nil
 }
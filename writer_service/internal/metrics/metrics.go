@@ -18,12 +18,24 @@ type WriterServiceMetrics struct {
 	GetProductByIdGrpcRequests prometheus.Counter
 	SearchProductGrpcRequests  prometheus.Counter
 
-	SuccessKafkaMessages prometheus.Counter
-	ErrorKafkaMessages   prometheus.Counter
+	GetProductAtVersionGrpcRequests prometheus.Counter
+	GetProductHistoryGrpcRequests   prometheus.Counter
+	StreamProductsGrpcRequests      prometheus.Counter
+
+	SuccessKafkaMessages       prometheus.Counter
+	ErrorKafkaMessages         prometheus.Counter
+	RetryKafkaMessages         prometheus.Counter
+	DlqFailedKafkaMessages     prometheus.Counter
+	CommandResultKafkaMessages prometheus.Counter
 
 	CreateProductKafkaMessages prometheus.Counter
 	UpdateProductKafkaMessages prometheus.Counter
 	DeleteProductKafkaMessages prometheus.Counter
+
+	OutboxPublishedMessages prometheus.Counter
+	OutboxPublishErrors     prometheus.Counter
+	OutboxPendingMessages   prometheus.Gauge
+	OutboxRelayLag          prometheus.Gauge
 }
 
 func NewWriterServiceMetrics(cfg *config.Config) *WriterServiceMetrics {
@@ -56,6 +68,18 @@ func NewWriterServiceMetrics(cfg *config.Config) *WriterServiceMetrics {
 			Name: fmt.Sprintf("%s_search_product_grpc_requests_total", cfg.ServiceName),
 			Help: "The total number of search product grpc requests",
 		}),
+		GetProductAtVersionGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_product_at_version_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of get product at version grpc requests",
+		}),
+		GetProductHistoryGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_get_product_history_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of get product history grpc requests",
+		}),
+		StreamProductsGrpcRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_stream_products_grpc_requests_total", cfg.ServiceName),
+			Help: "The total number of stream products grpc requests",
+		}),
 		CreateProductKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
 			Name: fmt.Sprintf("%s_create_product_kafka_messages_total", cfg.ServiceName),
 			Help: "The total number of create product kafka messages",
@@ -76,5 +100,33 @@ func NewWriterServiceMetrics(cfg *config.Config) *WriterServiceMetrics {
 			Name: fmt.Sprintf("%s_error_kafka_processed_messages_total", cfg.ServiceName),
 			Help: "The total number of error kafka processed messages",
 		}),
+		RetryKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_retry_kafka_processed_messages_total", cfg.ServiceName),
+			Help: "The total number of kafka messages sent to delayed retry topics",
+		}),
+		DlqFailedKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_dlq_publish_failed_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of failed dead letter publish attempts, the publish is retried until it succeeds",
+		}),
+		CommandResultKafkaMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_command_result_kafka_messages_total", cfg.ServiceName),
+			Help: "The total number of published command results",
+		}),
+		OutboxPublishedMessages: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_outbox_published_messages_total", cfg.ServiceName),
+			Help: "The total number of outbox messages published to kafka",
+		}),
+		OutboxPublishErrors: promauto.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_outbox_publish_errors_total", cfg.ServiceName),
+			Help: "The total number of failed outbox relay batches",
+		}),
+		OutboxPendingMessages: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("%s_outbox_pending_messages", cfg.ServiceName),
+			Help: "The number of outbox messages not yet published",
+		}),
+		OutboxRelayLag: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("%s_outbox_relay_lag_seconds", cfg.ServiceName),
+			Help: "Age in seconds of the oldest unpublished outbox message",
+		}),
 	}
 }
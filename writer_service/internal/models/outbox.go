@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// OutboxMessage kafka message stored in the same transaction as the product change
+type OutboxMessage struct {
+	ID          int64          `json:"id"`
+	AggregateID uuid.UUID      `json:"aggregateId"`
+	Topic       string         `json:"topic"`
+	Payload     []byte         `json:"payload"`
+	Headers     []kafka.Header `json:"headers"`
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
+// ToKafkaMessage build kafka message from outbox message keyed by aggregate id
+func (m *OutboxMessage) ToKafkaMessage() kafka.Message {
+	return kafka.Message{
+		Topic:   m.Topic,
+		Key:     []byte(m.AggregateID.String()),
+		Value:   m.Payload,
+		Headers: m.Headers,
+		Time:    time.Now().UTC(),
+	}
+}
@@ -14,4 +14,5 @@ type Product struct {
 	Price       float64   `json:"price"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
+	Version     int64     `json:"version"`
 }
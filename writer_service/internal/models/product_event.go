@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	ProductCreatedEventType = "ProductCreated"
+	ProductUpdatedEventType = "ProductUpdated"
+	ProductDeletedEventType = "ProductDeleted"
+)
+
+// ProductEvent append only product_events row
+type ProductEvent struct {
+	AggregateID uuid.UUID       `json:"aggregateId"`
+	Version     int64           `json:"version"`
+	EventType   string          `json:"eventType"`
+	Data        json.RawMessage `json:"data"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// ProductSnapshot product aggregate state at version
+type ProductSnapshot struct {
+	AggregateID uuid.UUID       `json:"aggregateId"`
+	Version     int64           `json:"version"`
+	Data        json.RawMessage `json:"data"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
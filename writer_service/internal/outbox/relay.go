@@ -0,0 +1,163 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
+	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/metrics"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	cleanupInterval       = 10 * time.Minute
+	defaultPollInterval   = 500 * time.Millisecond
+	defaultBatchSize      = 100
+	defaultRetentionHours = 24
+)
+
+// Relay publishes pending outbox messages to kafka, messages are marked as sent only after the broker acknowledged them (at-least-once).
+// Only the instance holding the relay lock publishes, so the messages of a product are published in outbox order.
+type Relay struct {
+	log           logger.Logger
+	cfg           *config.Config
+	transactor    postgres.Transactor
+	outboxRepo    repository.OutboxRepository
+	kafkaProducer kafkaClient.Producer
+	metrics       *metrics.WriterServiceMetrics
+}
+
+func NewRelay(
+	log logger.Logger,
+	cfg *config.Config,
+	transactor postgres.Transactor,
+	outboxRepo repository.OutboxRepository,
+	kafkaProducer kafkaClient.Producer,
+	metrics *metrics.WriterServiceMetrics,
+) *Relay {
+	return &Relay{log: log, cfg: cfg, transactor: transactor, outboxRepo: outboxRepo, kafkaProducer: kafkaProducer, metrics: metrics}
+}
+
+// Run polls the outbox table until ctx is done
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval())
+	defer ticker.Stop()
+
+	cleanupTicker := time.NewTicker(cleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Info("outbox relay stopped")
+			return
+		case <-cleanupTicker.C:
+			r.cleanup(ctx)
+		case <-ticker.C:
+			for {
+				published, err := r.publishBatch(ctx)
+				if err != nil {
+					r.metrics.OutboxPublishErrors.Inc()
+					r.log.WarnMsg("publishBatch", err)
+					break
+				}
+				if published < r.batchSize() || ctx.Err() != nil {
+					break
+				}
+			}
+			r.updateLagMetrics(ctx)
+		}
+	}
+}
+
+// publishBatch publishes the oldest pending messages under the relay lock, nothing while another instance holds it
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	var published int
+
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := r.outboxRepo.TryLockRelay(ctx)
+		if err != nil || !locked {
+			return err
+		}
+
+		messages, err := r.outboxRepo.GetPendingMessages(ctx, r.batchSize())
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		kafkaMessages := make([]kafka.Message, 0, len(messages))
+		ids := make([]int64, 0, len(messages))
+		for _, msg := range messages {
+			kafkaMessages = append(kafkaMessages, msg.ToKafkaMessage())
+			ids = append(ids, msg.ID)
+		}
+
+		if err := r.kafkaProducer.PublishMessage(ctx, kafkaMessages...); err != nil {
+			return errors.Wrap(err, "kafkaProducer.PublishMessage")
+		}
+
+		if err := r.outboxRepo.MarkSent(ctx, ids); err != nil {
+			return err
+		}
+
+		published = len(messages)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.metrics.OutboxPublishedMessages.Add(float64(published))
+	return published, nil
+}
+
+func (r *Relay) updateLagMetrics(ctx context.Context) {
+	lag, pending, err := r.outboxRepo.GetPendingStats(ctx)
+	if err != nil {
+		r.log.WarnMsg("outboxRepo.GetPendingStats", err)
+		return
+	}
+
+	r.metrics.OutboxRelayLag.Set(lag.Seconds())
+	r.metrics.OutboxPendingMessages.Set(float64(pending))
+}
+
+func (r *Relay) cleanup(ctx context.Context) {
+	deleted, err := r.outboxRepo.DeleteSentMessages(ctx, r.retentionHours())
+	if err != nil {
+		r.log.WarnMsg("outboxRepo.DeleteSentMessages", err)
+		return
+	}
+	if deleted > 0 {
+		r.log.Infof("outbox relay deleted sent messages: %d", deleted)
+	}
+}
+
+func (r *Relay) pollInterval() time.Duration {
+	if r.cfg.OutboxRelay.PollIntervalMs > 0 {
+		return time.Duration(r.cfg.OutboxRelay.PollIntervalMs) * time.Millisecond
+	}
+	return defaultPollInterval
+}
+
+func (r *Relay) batchSize() int {
+	if r.cfg.OutboxRelay.BatchSize > 0 {
+		return r.cfg.OutboxRelay.BatchSize
+	}
+	return defaultBatchSize
+}
+
+func (r *Relay) retentionHours() int {
+	if r.cfg.OutboxRelay.RetentionHours > 0 {
+		return r.cfg.OutboxRelay.RetentionHours
+	}
+	return defaultRetentionHours
+}
@@ -0,0 +1,101 @@
+package aggregate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+var (
+	ErrAggregateNotFound = errors.New("product aggregate not found")
+)
+
+// ProductAggregate product state rehydrated from product_events
+type ProductAggregate struct {
+	ID      uuid.UUID       `json:"id"`
+	Version int64           `json:"version"`
+	Product *models.Product `json:"product"`
+	Deleted bool            `json:"deleted"`
+}
+
+func NewProductAggregate(id uuid.UUID) *ProductAggregate {
+	return &ProductAggregate{ID: id}
+}
+
+// Apply event to the aggregate, events must be applied in version order
+func (a *ProductAggregate) Apply(event *models.ProductEvent) error {
+	if event.Version != a.Version+1 {
+		return errors.Errorf("event version %d does not follow aggregate version %d", event.Version, a.Version)
+	}
+
+	switch event.EventType {
+	case models.ProductCreatedEventType, models.ProductUpdatedEventType:
+		var product models.Product
+		if err := json.Unmarshal(event.Data, &product); err != nil {
+			return errors.Wrap(err, "json.Unmarshal")
+		}
+		a.Product = &product
+		a.Deleted = false
+	case models.ProductDeletedEventType:
+		a.Deleted = true
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	a.Version = event.Version
+	return nil
+}
+
+// RestoreSnapshot replaces aggregate state with the snapshot
+func (a *ProductAggregate) RestoreSnapshot(snapshot *models.ProductSnapshot) error {
+	if err := json.Unmarshal(snapshot.Data, a); err != nil {
+		return errors.Wrap(err, "json.Unmarshal")
+	}
+	a.ID = snapshot.AggregateID
+	a.Version = snapshot.Version
+	return nil
+}
+
+func (a *ProductAggregate) ToSnapshot() (*models.ProductSnapshot, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal")
+	}
+	return &models.ProductSnapshot{AggregateID: a.ID, Version: a.Version, Data: data}, nil
+}
+
+// Load rehydrates aggregate at version from the nearest snapshot and the following events, version 0 means the latest
+func Load(ctx context.Context, eventStore repository.EventStore, id uuid.UUID, version int64) (*ProductAggregate, error) {
+	aggregate := NewProductAggregate(id)
+
+	snapshot, err := eventStore.GetSnapshot(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot != nil {
+		if err := aggregate.RestoreSnapshot(snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	events, err := eventStore.LoadEvents(ctx, id, aggregate.Version, version)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if err := aggregate.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+
+	if aggregate.Version == 0 || (version != 0 && aggregate.Version != version) {
+		return nil, ErrAggregateNotFound
+	}
+
+	return aggregate, nil
+}
@@ -23,15 +23,17 @@ func NewCreateProductCommand(productID uuid.UUID, name string, description strin
 	return &CreateProductCommand{ProductID: productID, Name: name, Description: description, Price: price}
 }
 
+// UpdateProductCommand ExpectedVersion 0 updates regardless of the current version
 type UpdateProductCommand struct {
-	ProductID   uuid.UUID `json:"productId" validate:"required,gte=0,lte=255"`
-	Name        string    `json:"name" validate:"required,gte=0,lte=255"`
-	Description string    `json:"description" validate:"required,gte=0,lte=5000"`
-	Price       float64   `json:"price" validate:"required,gte=0"`
+	ProductID       uuid.UUID `json:"productId" validate:"required,gte=0,lte=255"`
+	Name            string    `json:"name" validate:"required,gte=0,lte=255"`
+	Description     string    `json:"description" validate:"required,gte=0,lte=5000"`
+	Price           float64   `json:"price" validate:"required,gte=0"`
+	ExpectedVersion int64     `json:"expectedVersion" validate:"gte=0"`
 }
 
-func NewUpdateProductCommand(productID uuid.UUID, name string, description string, price float64) *UpdateProductCommand {
-	return &UpdateProductCommand{ProductID: productID, Name: name, Description: description, Price: price}
+func NewUpdateProductCommand(productID uuid.UUID, name string, description string, price float64, expectedVersion int64) *UpdateProductCommand {
+	return &UpdateProductCommand{ProductID: productID, Name: name, Description: description, Price: price, ExpectedVersion: expectedVersion}
 }
 
 type DeleteProductCommand struct {
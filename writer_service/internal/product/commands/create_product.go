@@ -2,19 +2,17 @@ package commands
 
 import (
 	"context"
-	"time"
 
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
-	"github.com/herhu/Microservices-PR/pkg/tracing"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/config"
 	"github.com/herhu/Microservices-PR/writer_service/internal/models"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
 	"github.com/herhu/Microservices-PR/writer_service/mappers"
 	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"github.com/pkg/errors"
 )
 
 type CreateProductCmdHandler interface {
@@ -22,39 +20,47 @@ type CreateProductCmdHandler interface {
 }
 
 type createProductHandler struct {
-	log           logger.Logger
-	cfg           *config.Config
-	pgRepo        repository.Repository
-	kafkaProducer kafkaClient.Producer
+	log        logger.Logger
+	cfg        *config.Config
+	pgRepo     repository.Repository
+	outboxRepo repository.OutboxRepository
+	eventStore repository.EventStore
+	transactor postgres.Transactor
 }
 
-func NewCreateProductHandler(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, kafkaProducer kafkaClient.Producer) *createProductHandler {
-	return &createProductHandler{log: log, cfg: cfg, pgRepo: pgRepo, kafkaProducer: kafkaProducer}
+func NewCreateProductHandler(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, outboxRepo repository.OutboxRepository, eventStore repository.EventStore, transactor postgres.Transactor) *createProductHandler {
+	return &createProductHandler{log: log, cfg: cfg, pgRepo: pgRepo, outboxRepo: outboxRepo, eventStore: eventStore, transactor: transactor}
 }
 
+// Handle creates the product, a create of an existing product id is a redelivered command and succeeds
+// without appending another event
 func (c *createProductHandler) Handle(ctx context.Context, command *CreateProductCommand) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "createProductHandler.Handle")
 	defer span.Finish()
 
 	productDto := &models.Product{ProductID: command.ProductID, Name: command.Name, Description: command.Description, Price: command.Price}
 
-	product, err := c.pgRepo.CreateProduct(ctx, productDto)
-	if err != nil {
-		return err
-	}
-
-	msg := &kafkaMessages.ProductCreated{Product: mappers.ProductToGrpcMessage(product)}
-	msgBytes, err := proto.Marshal(msg)
-	if err != nil {
-		return err
-	}
-
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.ProductCreated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := c.pgRepo.CreateProduct(ctx, productDto)
+		if err != nil {
+			if errors.Is(err, repository.ErrProductExists) {
+				c.log.Infof("product %s already exists, create skipped", command.ProductID)
+				return nil
+			}
+			return err
+		}
+
+		if err := appendEvent(ctx, c.cfg, c.eventStore, product.ProductID, models.ProductCreatedEventType, product); err != nil {
+			return err
+		}
+
+		msg := &kafkaMessages.ProductCreated{Product: mappers.ProductToGrpcMessage(product)}
+		envelope := kafkaClient.NewEnvelope(ctx, kafkaMessages.ProductCreatedType, kafkaMessages.ProductCreatedSchemaVersion, product.ProductID.String(), c.cfg.ServiceName)
+		outboxMessage, err := newOutboxMessage(span, product.ProductID, c.cfg.KafkaTopics.ProductCreated.TopicName, msg, envelope)
+		if err != nil {
+			return err
+		}
+
+		return c.outboxRepo.SaveMessage(ctx, outboxMessage)
+	})
 }
@@ -2,17 +2,15 @@ package commands
 
 import (
 	"context"
-	"time"
 
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
-	"github.com/herhu/Microservices-PR/pkg/tracing"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
 	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 )
 
 type DeleteProductCmdHandler interface {
@@ -20,36 +18,46 @@ type DeleteProductCmdHandler interface {
 }
 
 type deleteProductHandler struct {
-	log           logger.Logger
-	cfg           *config.Config
-	pgRepo        repository.Repository
-	kafkaProducer kafkaClient.Producer
+	log        logger.Logger
+	cfg        *config.Config
+	pgRepo     repository.Repository
+	outboxRepo repository.OutboxRepository
+	eventStore repository.EventStore
+	transactor postgres.Transactor
 }
 
-func NewDeleteProductHandler(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, kafkaProducer kafkaClient.Producer) *deleteProductHandler {
-	return &deleteProductHandler{log: log, cfg: cfg, pgRepo: pgRepo, kafkaProducer: kafkaProducer}
+func NewDeleteProductHandler(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, outboxRepo repository.OutboxRepository, eventStore repository.EventStore, transactor postgres.Transactor) *deleteProductHandler {
+	return &deleteProductHandler{log: log, cfg: cfg, pgRepo: pgRepo, outboxRepo: outboxRepo, eventStore: eventStore, transactor: transactor}
 }
 
+// Handle deletes the product and records the delete event, returns ErrProductNotFound without writing
+// any event or outbox message when the product does not exist
 func (c *deleteProductHandler) Handle(ctx context.Context, command *DeleteProductCommand) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteProductHandler.Handle")
 	defer span.Finish()
 
-	if err := c.pgRepo.DeleteProductByID(ctx, command.ProductID); err != nil {
-		return err
-	}
-
-	msg := &kafkaMessages.ProductDeleted{ProductID: command.ProductID.String()}
-	msgBytes, err := proto.Marshal(msg)
-	if err != nil {
-		return err
-	}
-
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.ProductDeleted.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
-
-	return c.kafkaProducer.PublishMessage(ctx, message)
+	return c.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		version, err := c.pgRepo.DeleteProductByID(ctx, command.ProductID)
+		if err != nil {
+			return err
+		}
+		// a missing product has no event stream to append the delete to
+		if version == 0 {
+			return repository.ErrProductNotFound
+		}
+
+		deleted := map[string]interface{}{"productId": command.ProductID, "version": version + 1}
+		if err := appendEvent(ctx, c.cfg, c.eventStore, command.ProductID, models.ProductDeletedEventType, deleted); err != nil {
+			return err
+		}
+
+		msg := &kafkaMessages.ProductDeleted{ProductID: command.ProductID.String(), Version: version + 1}
+		envelope := kafkaClient.NewEnvelope(ctx, kafkaMessages.ProductDeletedType, kafkaMessages.ProductDeletedSchemaVersion, command.ProductID.String(), c.cfg.ServiceName)
+		outboxMessage, err := newOutboxMessage(span, command.ProductID, c.cfg.KafkaTopics.ProductDeleted.TopicName, msg, envelope)
+		if err != nil {
+			return err
+		}
+
+		return c.outboxRepo.SaveMessage(ctx, outboxMessage)
+	})
 }
@@ -2,19 +2,16 @@ package commands
 
 import (
 	"context"
-	"time"
 
 	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
-	"github.com/herhu/Microservices-PR/pkg/tracing"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/config"
 	"github.com/herhu/Microservices-PR/writer_service/internal/models"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
 	"github.com/herhu/Microservices-PR/writer_service/mappers"
 	"github.com/opentracing/opentracing-go"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 )
 
 type UpdateProductCmdHandler interface {
@@ -22,39 +19,47 @@ type UpdateProductCmdHandler interface {
 }
 
 type updateProductHandler struct {
-	log           logger.Logger
-	cfg           *config.Config
-	pgRepo        repository.Repository
-	kafkaProducer kafkaClient.Producer
+	log        logger.Logger
+	cfg        *config.Config
+	pgRepo     repository.Repository
+	outboxRepo repository.OutboxRepository
+	eventStore repository.EventStore
+	transactor postgres.Transactor
 }
 
-func NewUpdateProductHandler(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, kafkaProducer kafkaClient.Producer) *updateProductHandler {
-	return &updateProductHandler{log: log, cfg: cfg, pgRepo: pgRepo, kafkaProducer: kafkaProducer}
+func NewUpdateProductHandler(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, outboxRepo repository.OutboxRepository, eventStore repository.EventStore, transactor postgres.Transactor) *updateProductHandler {
+	return &updateProductHandler{log: log, cfg: cfg, pgRepo: pgRepo, outboxRepo: outboxRepo, eventStore: eventStore, transactor: transactor}
 }
 
 func (c *updateProductHandler) Handle(ctx context.Context, command *UpdateProductCommand) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "updateProductHandler.Handle")
 	defer span.Finish()
 
-	productDto := &models.Product{ProductID: command.ProductID, Name: command.Name, Description: command.Description, Price: command.Price}
-
-	product, err := c.pgRepo.UpdateProduct(ctx, productDto)
-	if err != nil {
-		return err
+	productDto := &models.Product{
+		ProductID:   command.ProductID,
+		Name:        command.Name,
+		Description: command.Description,
+		Price:       command.Price,
+		Version:     command.ExpectedVersion,
 	}
 
-	msg := &kafkaMessages.ProductUpdated{Product: mappers.ProductToGrpcMessage(product)}
-	msgBytes, err := proto.Marshal(msg)
-	if err != nil {
-		return err
-	}
+	return c.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := c.pgRepo.UpdateProduct(ctx, productDto)
+		if err != nil {
+			return err
+		}
 
-	message := kafka.Message{
-		Topic:   c.cfg.KafkaTopics.ProductUpdated.TopicName,
-		Value:   msgBytes,
-		Time:    time.Now().UTC(),
-		Headers: tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()),
-	}
+		if err := appendEvent(ctx, c.cfg, c.eventStore, product.ProductID, models.ProductUpdatedEventType, product); err != nil {
+			return err
+		}
+
+		msg := &kafkaMessages.ProductUpdated{Product: mappers.ProductToGrpcMessage(product)}
+		envelope := kafkaClient.NewEnvelope(ctx, kafkaMessages.ProductUpdatedType, kafkaMessages.ProductUpdatedSchemaVersion, product.ProductID.String(), c.cfg.ServiceName)
+		outboxMessage, err := newOutboxMessage(span, product.ProductID, c.cfg.KafkaTopics.ProductUpdated.TopicName, msg, envelope)
+		if err != nil {
+			return err
+		}
 
-	return c.kafkaProducer.PublishMessage(ctx, message)
+		return c.outboxRepo.SaveMessage(ctx, outboxMessage)
+	})
 }
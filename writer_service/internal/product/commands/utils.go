@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	"github.com/herhu/Microservices-PR/pkg/tracing"
+	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/aggregate"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// newOutboxMessage outbox message with tracing and envelope headers
+func newOutboxMessage(span opentracing.Span, aggregateID uuid.UUID, topic string, msg proto.Message, envelope *kafkaClient.Envelope) (*models.OutboxMessage, error) {
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxMessage{
+		AggregateID: aggregateID,
+		Topic:       topic,
+		Payload:     msgBytes,
+		Headers:     kafkaClient.WithEnvelope(tracing.GetKafkaTracingHeadersFromSpanCtx(span.Context()), envelope),
+	}, nil
+}
+
+// appendEvent appends product event and takes an aggregate snapshot every cfg.EventStore.SnapshotFrequency events
+func appendEvent(ctx context.Context, cfg *config.Config, eventStore repository.EventStore, aggregateID uuid.UUID, eventType string, data interface{}) error {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	event, err := eventStore.AppendEvent(ctx, aggregateID, eventType, dataBytes)
+	if err != nil {
+		return err
+	}
+
+	if cfg.EventStore.SnapshotFrequency <= 0 || event.Version%int64(cfg.EventStore.SnapshotFrequency) != 0 {
+		return nil
+	}
+
+	productAggregate, err := aggregate.Load(ctx, eventStore, aggregateID, event.Version)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := productAggregate.ToSnapshot()
+	if err != nil {
+		return err
+	}
+
+	return eventStore.SaveSnapshot(ctx, snapshot)
+}
@@ -2,17 +2,22 @@ package grpc
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/herhu/Microservices-PR/pkg/logger"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	"github.com/herhu/Microservices-PR/writer_service/config"
 	"github.com/herhu/Microservices-PR/writer_service/internal/metrics"
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/aggregate"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/commands"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/queries"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/service"
 	"github.com/herhu/Microservices-PR/writer_service/mappers"
 	writerService "github.com/herhu/Microservices-PR/writer_service/proto/product_writer"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -70,7 +75,7 @@ func (s *grpcService) UpdateProduct(ctx context.Context, req *writerService.Upda
 		return nil, s.errResponse(codes.InvalidArgument, err)
 	}
 
-	command := commands.NewUpdateProductCommand(productUUID, req.GetName(), req.GetDescription(), req.GetPrice())
+	command := commands.NewUpdateProductCommand(productUUID, req.GetName(), req.GetDescription(), req.GetPrice(), req.GetExpectedVersion())
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
 		return nil, s.errResponse(codes.InvalidArgument, err)
@@ -79,6 +84,9 @@ func (s *grpcService) UpdateProduct(ctx context.Context, req *writerService.Upda
 	err = s.ps.Commands.UpdateProduct.Handle(ctx, command)
 	if err != nil {
 		s.log.WarnMsg("UpdateProduct.Handle", err)
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, s.errResponse(codes.Aborted, err)
+		}
 		return nil, s.errResponse(codes.Internal, err)
 	}
 
@@ -114,6 +122,117 @@ func (s *grpcService) GetProductById(ctx context.Context, req *writerService.Get
 	return &writerService.GetProductByIdRes{Product: mappers.WriterProductToGrpc(product)}, nil
 }
 
+func (s *grpcService) GetProductAtVersion(ctx context.Context, req *writerService.GetProductAtVersionReq) (*writerService.GetProductAtVersionRes, error) {
+	s.metrics.GetProductAtVersionGrpcRequests.Inc()
+
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.GetProductAtVersion")
+	defer span.Finish()
+
+	productUUID, err := uuid.FromString(req.GetProductID())
+	if err != nil {
+		s.log.WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+
+	var at time.Time
+	if req.GetAt() != nil {
+		at = req.GetAt().AsTime()
+	}
+
+	query := queries.NewGetProductAtVersionQuery(productUUID, req.GetVersion(), at)
+	if err := s.v.StructCtx(ctx, query); err != nil {
+		s.log.WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+
+	productAggregate, err := s.ps.Queries.GetProductAtVersion.Handle(ctx, query)
+	if err != nil {
+		s.log.WarnMsg("GetProductAtVersion.Handle", err)
+		return nil, s.errResponse(aggregateErrCode(err), err)
+	}
+
+	res := &writerService.GetProductAtVersionRes{Version: productAggregate.Version, Deleted: productAggregate.Deleted}
+	if productAggregate.Product != nil {
+		res.Product = mappers.WriterProductToGrpc(productAggregate.Product)
+	}
+
+	s.metrics.SuccessGrpcRequests.Inc()
+	return res, nil
+}
+
+func (s *grpcService) GetProductHistory(ctx context.Context, req *writerService.GetProductHistoryReq) (*writerService.GetProductHistoryRes, error) {
+	s.metrics.GetProductHistoryGrpcRequests.Inc()
+
+	ctx, span := tracing.StartGrpcServerTracerSpan(ctx, "grpcService.GetProductHistory")
+	defer span.Finish()
+
+	productUUID, err := uuid.FromString(req.GetProductID())
+	if err != nil {
+		s.log.WarnMsg("uuid.FromString", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+
+	query := queries.NewGetProductHistoryQuery(productUUID)
+	if err := s.v.StructCtx(ctx, query); err != nil {
+		s.log.WarnMsg("validate", err)
+		return nil, s.errResponse(codes.InvalidArgument, err)
+	}
+
+	events, err := s.ps.Queries.GetProductHistory.Handle(ctx, query)
+	if err != nil {
+		s.log.WarnMsg("GetProductHistory.Handle", err)
+		return nil, s.errResponse(aggregateErrCode(err), err)
+	}
+
+	grpcEvents := make([]*writerService.ProductEvent, 0, len(events))
+	for _, event := range events {
+		grpcEvent, err := mappers.ProductEventToGrpc(event)
+		if err != nil {
+			s.log.WarnMsg("mappers.ProductEventToGrpc", err)
+			return nil, s.errResponse(codes.Internal, err)
+		}
+		grpcEvents = append(grpcEvents, grpcEvent)
+	}
+
+	s.metrics.SuccessGrpcRequests.Inc()
+	return &writerService.GetProductHistoryRes{Events: grpcEvents}, nil
+}
+
+// StreamProducts streams all products in batches, used by the reader to rebuild its projection
+func (s *grpcService) StreamProducts(req *writerService.StreamProductsReq, stream writerService.WriterService_StreamProductsServer) error {
+	s.metrics.StreamProductsGrpcRequests.Inc()
+
+	ctx, span := tracing.StartGrpcServerTracerSpan(stream.Context(), "grpcService.StreamProducts")
+	defer span.Finish()
+
+	query := queries.NewStreamProductsQuery(int(req.GetBatchSize()))
+	if err := s.v.StructCtx(ctx, query); err != nil {
+		s.log.WarnMsg("validate", err)
+		return s.errResponse(codes.InvalidArgument, err)
+	}
+
+	if err := s.ps.Queries.StreamProducts.Handle(ctx, query, func(products []*models.Product) error {
+		grpcProducts := make([]*writerService.Product, 0, len(products))
+		for _, product := range products {
+			grpcProducts = append(grpcProducts, mappers.WriterProductToGrpc(product))
+		}
+		return stream.Send(&writerService.StreamProductsRes{Products: grpcProducts})
+	}); err != nil {
+		s.log.WarnMsg("StreamProducts.Handle", err)
+		return s.errResponse(codes.Internal, err)
+	}
+
+	s.metrics.SuccessGrpcRequests.Inc()
+	return nil
+}
+
+func aggregateErrCode(err error) codes.Code {
+	if errors.Is(err, aggregate.ErrAggregateNotFound) {
+		return codes.NotFound
+	}
+	return codes.Internal
+}
+
 func (s *grpcService) errResponse(c codes.Code, err error) error {
 	s.metrics.ErrorGrpcRequests.Inc()
 	return status.Error(c, err.Error())
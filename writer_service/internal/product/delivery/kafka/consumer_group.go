@@ -2,13 +2,17 @@ package kafka
 
 import (
 	"context"
-	"sync"
 
 	"github.com/go-playground/validator"
+	"github.com/herhu/Microservices-PR/pkg/identity"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/upcasters"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/config"
 	"github.com/herhu/Microservices-PR/writer_service/internal/metrics"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/service"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -17,42 +21,67 @@ const (
 )
 
 type productMessageProcessor struct {
-	log     logger.Logger
-	cfg     *config.Config
-	v       *validator.Validate
-	ps      *service.ProductService
-	metrics *metrics.WriterServiceMetrics
+	log           logger.Logger
+	cfg           *config.Config
+	v             *validator.Validate
+	ps            *service.ProductService
+	metrics       *metrics.WriterServiceMetrics
+	kafkaProducer kafkaClient.Producer
+	routes        kafkaClient.TopicRoutes
+	failures      kafkaClient.FailureHandler
 }
 
-func NewProductMessageProcessor(log logger.Logger, cfg *config.Config, v *validator.Validate, ps *service.ProductService, metrics *metrics.WriterServiceMetrics) *productMessageProcessor {
-	return &productMessageProcessor{log: log, cfg: cfg, v: v, ps: ps, metrics: metrics}
+func NewProductMessageProcessor(
+	log logger.Logger,
+	cfg *config.Config,
+	v *validator.Validate,
+	ps *service.ProductService,
+	metrics *metrics.WriterServiceMetrics,
+	kafkaProducer kafkaClient.Producer,
+) *productMessageProcessor {
+	s := &productMessageProcessor{log: log, cfg: cfg, v: v, ps: ps, metrics: metrics, kafkaProducer: kafkaProducer, routes: cfg.KafkaTopics.Routes()}
+	s.failures = kafkaClient.NewFailureHandler(log, cfg.Kafka, s.routes, kafkaProducer, kafkaClient.FailureMetrics{
+		Error:     metrics.ErrorKafkaMessages,
+		Retry:     metrics.RetryKafkaMessages,
+		DlqFailed: metrics.DlqFailedKafkaMessages,
+	}, s.rejectCommand)
+	return s
 }
 
-func (s *productMessageProcessor) ProcessMessages(ctx context.Context, r *kafka.Reader, wg *sync.WaitGroup, workerID int) {
-	defer wg.Done()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-
-		m, err := r.FetchMessage(ctx)
-		if err != nil {
-			s.log.Warnf("workerID: %v, err: %v", workerID, err)
-			continue
-		}
-
-		s.logProcessMessage(m, workerID)
-
-		switch m.Topic {
-		case s.cfg.KafkaTopics.ProductCreate.TopicName:
-			s.processCreateProduct(ctx, r, m)
-		case s.cfg.KafkaTopics.ProductUpdate.TopicName:
-			s.processUpdateProduct(ctx, r, m)
-		case s.cfg.KafkaTopics.ProductDelete.TopicName:
-			s.processDeleteProduct(ctx, r, m)
-		}
+func (s *productMessageProcessor) ProcessMessage(ctx context.Context, r kafkaClient.Reader, m kafka.Message, workerID int) {
+	s.logProcessMessage(m, workerID)
+
+	envelope, err := kafkaClient.MessageEnvelope(m, s.routes.EventType(m.Topic))
+	if err != nil {
+		s.log.WarnMsg("MessageEnvelope", err)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
+		return
+	}
+
+	m, err = upcasters.Products.UpcastMessage(m, envelope)
+	if err != nil {
+		s.log.WarnMsg("UpcastMessage", err)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
+		return
+	}
+
+	if envelope.CorrelationID != "" {
+		ctx = kafkaClient.ContextWithCorrelationID(ctx, envelope.CorrelationID)
+	}
+	if envelope.CallerSubject != "" {
+		ctx = identity.NewContext(ctx, &identity.Identity{Subject: envelope.CallerSubject, Roles: envelope.CallerRoles})
+	}
+
+	switch envelope.EventType {
+	case kafkaMessages.ProductCreateType:
+		s.processCreateProduct(ctx, r, m)
+	case kafkaMessages.ProductUpdateType:
+		s.processUpdateProduct(ctx, r, m)
+	case kafkaMessages.ProductDeleteType:
+		s.processDeleteProduct(ctx, r, m)
+	default:
+		err := errors.Errorf("unknown event type: %s", envelope.EventType)
+		s.log.WarnMsg("ProcessMessage", err)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
 	}
 }
@@ -2,9 +2,8 @@ package kafka
 
 import (
 	"context"
-	"time"
 
-	"github.com/avast/retry-go"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/commands"
@@ -13,16 +12,7 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-const (
-	retryAttempts = 3
-	retryDelay    = 300 * time.Millisecond
-)
-
-var (
-	retryOptions = []retry.Option{retry.Attempts(retryAttempts), retry.Delay(retryDelay), retry.DelayType(retry.BackOffDelay)}
-)
-
-func (s *productMessageProcessor) processCreateProduct(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+func (s *productMessageProcessor) processCreateProduct(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
 	s.metrics.CreateProductKafkaMessages.Inc()
 
 	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "productMessageProcessor.processCreateProduct")
@@ -31,29 +21,27 @@ func (s *productMessageProcessor) processCreateProduct(ctx context.Context, r *k
 	var msg kafkaMessages.ProductCreate
 	if err := proto.Unmarshal(m.Value, &msg); err != nil {
 		s.log.WarnMsg("proto.Unmarshal", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
 		return
 	}
 
 	proUUID, err := uuid.FromString(msg.GetProductID())
 	if err != nil {
 		s.log.WarnMsg("proto.Unmarshal", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
 	command := commands.NewCreateProductCommand(proUUID, msg.GetName(), msg.GetDescription(), msg.GetPrice())
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
-	if err := retry.Do(func() error {
-		return s.ps.Commands.CreateProduct.Handle(ctx, command)
-	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+	if err := s.ps.Commands.CreateProduct.Handle(ctx, command); err != nil {
 		s.log.WarnMsg("CreateProduct.Handle", err)
-		s.metrics.ErrorKafkaMessages.Inc()
+		s.failCommand(ctx, r, m, err)
 		return
 	}
 
@@ -3,7 +3,7 @@ package kafka
 import (
 	"context"
 
-	"github.com/avast/retry-go"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/commands"
@@ -12,7 +12,9 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func (s *productMessageProcessor) processUpdateProduct(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+// processUpdateProduct a retried update is redelivered behind newer commands of the product, only updates with an
+// expected version are guarded against overwriting them, they fail with a version conflict and are dead lettered
+func (s *productMessageProcessor) processUpdateProduct(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
 	s.metrics.UpdateProductKafkaMessages.Inc()
 
 	ctx, span := tracing.StartKafkaConsumerTracerSpan(ctx, m.Headers, "productMessageProcessor.processUpdateProduct")
@@ -21,29 +23,27 @@ func (s *productMessageProcessor) processUpdateProduct(ctx context.Context, r *k
 	msg := &kafkaMessages.ProductUpdate{}
 	if err := proto.Unmarshal(m.Value, msg); err != nil {
 		s.log.WarnMsg("proto.Unmarshal", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageUnmarshal, err, 1)
 		return
 	}
 
 	proUUID, err := uuid.FromString(msg.GetProductID())
 	if err != nil {
 		s.log.WarnMsg("proto.Unmarshal", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
-	command := commands.NewUpdateProductCommand(proUUID, msg.GetName(), msg.GetDescription(), msg.GetPrice())
+	command := commands.NewUpdateProductCommand(proUUID, msg.GetName(), msg.GetDescription(), msg.GetPrice(), msg.GetExpectedVersion())
 	if err := s.v.StructCtx(ctx, command); err != nil {
 		s.log.WarnMsg("validate", err)
-		s.commitErrMessage(ctx, r, m)
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageValidation, err, 1)
 		return
 	}
 
-	if err := retry.Do(func() error {
-		return s.ps.Commands.UpdateProduct.Handle(ctx, command)
-	}, append(retryOptions, retry.Context(ctx))...); err != nil {
+	if err := s.ps.Commands.UpdateProduct.Handle(ctx, command); err != nil {
 		s.log.WarnMsg("UpdateProduct.Handle", err)
-		s.metrics.ErrorKafkaMessages.Inc()
+		s.failCommand(ctx, r, m, err)
 		return
 	}
 
@@ -2,23 +2,138 @@ package kafka
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/go-playground/validator"
+	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
+	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
 	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	publishAttempts = 3
+	publishDelay    = 300 * time.Millisecond
+
+	pgDataExceptionClass      = "22"
+	pgIntegrityViolationClass = "23"
 )
 
-func (s *productMessageProcessor) commitMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
+var (
+	publishRetryOptions = []retry.Option{retry.Attempts(publishAttempts), retry.Delay(publishDelay), retry.DelayType(retry.BackOffDelay)}
+)
+
+func (s *productMessageProcessor) commitMessage(ctx context.Context, r kafkaClient.Reader, m kafka.Message) {
 	s.metrics.SuccessKafkaMessages.Inc()
+	s.publishCommandResult(ctx, m, kafkaMessages.CommandAccepted, "")
 	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
 	if err := r.CommitMessages(ctx, m); err != nil {
 		s.log.WarnMsg("commitMessage", err)
 	}
 }
 
-func (s *productMessageProcessor) commitErrMessage(ctx context.Context, r *kafka.Reader, m kafka.Message) {
-	s.metrics.ErrorKafkaMessages.Inc()
-	s.log.KafkaLogCommittedMessage(m.Topic, m.Partition, m.Offset)
-	if err := r.CommitMessages(ctx, m); err != nil {
-		s.log.WarnMsg("commitMessage", err)
+// failCommand dead letters commands that fail the same way on every attempt and retries the others
+func (s *productMessageProcessor) failCommand(ctx context.Context, r kafkaClient.Reader, m kafka.Message, err error) {
+	if !retryable(err) {
+		s.failures.CommitErrMessage(ctx, r, m, kafkaClient.StageHandle, err, kafkaClient.GetRetryAttempt(m)+1)
+		return
+	}
+	s.failures.RetryErrMessage(ctx, r, m, err)
+}
+
+// retryable reports whether the command can succeed when processed again, conflicts, missing products,
+// invalid values and constraint violations can not
+func retryable(err error) bool {
+	var validationErrors validator.ValidationErrors
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict), errors.Is(err, repository.ErrProductNotFound):
+		return false
+	case errors.As(err, &validationErrors):
+		return false
+	case errors.As(err, &pgErr):
+		return !strings.HasPrefix(pgErr.Code, pgDataExceptionClass) && !strings.HasPrefix(pgErr.Code, pgIntegrityViolationClass)
+	default:
+		return true
+	}
+}
+
+// rejectCommand reports a dead lettered command as rejected
+func (s *productMessageProcessor) rejectCommand(ctx context.Context, m kafka.Message, stage string, reason error) {
+	s.publishCommandResult(ctx, m, kafkaMessages.CommandRejected, commandRejectedReason(stage, reason))
+}
+
+// commandRejectedReason the reason shown to the client, validation failures name the invalid fields
+// and failures of the writer itself are reported with a generic text, the full error is only logged
+func commandRejectedReason(stage string, reason error) string {
+	var validationErrors validator.ValidationErrors
+	switch {
+	case errors.As(reason, &validationErrors):
+		fields := make([]string, 0, len(validationErrors))
+		for _, fieldErr := range validationErrors {
+			fields = append(fields, fmt.Sprintf("%s (%s)", fieldErr.Field(), fieldErr.Tag()))
+		}
+		return fmt.Sprintf("invalid fields: %s", strings.Join(fields, ", "))
+	case errors.Is(reason, repository.ErrVersionConflict):
+		return repository.ErrVersionConflict.Error()
+	case errors.Is(reason, repository.ErrProductNotFound):
+		return repository.ErrProductNotFound.Error()
+	case stage == kafkaClient.StageValidation:
+		return "invalid command"
+	case stage == kafkaClient.StageUnmarshal:
+		return "malformed command"
+	default:
+		return "internal error"
+	}
+}
+
+// publishCommandResult reports the outcome of a command published with a command id, so the gateway can tell
+// the client. Failures are logged, the client then sees the command as pending until the status expires.
+func (s *productMessageProcessor) publishCommandResult(ctx context.Context, m kafka.Message, status string, reason string) {
+	command, err := kafkaClient.ParseEnvelope(m.Headers)
+	if err != nil || command.CommandID == "" {
+		return
+	}
+
+	result := &kafkaMessages.ProductCommandResult{
+		CommandID:   command.CommandID,
+		CommandType: command.EventType,
+		ProductID:   command.AggregateID,
+		Status:      status,
+		OccurredAt:  timestamppb.Now(),
+		Reason:      reason,
+	}
+
+	resultBytes, err := proto.Marshal(result)
+	if err != nil {
+		s.log.WarnMsg("proto.Marshal", err)
+		return
 	}
+
+	envelope := kafkaClient.NewEnvelope(ctx, kafkaMessages.ProductCommandResultType, kafkaMessages.ProductCommandResultSchemaVersion, command.AggregateID, s.cfg.ServiceName)
+	envelope.CommandID = command.CommandID
+
+	if err := retry.Do(func() error {
+		return s.kafkaProducer.PublishMessage(ctx, kafka.Message{
+			Topic:   s.cfg.KafkaTopics.CommandResult.TopicName,
+			Key:     []byte(command.CommandID),
+			Value:   resultBytes,
+			Time:    time.Now().UTC(),
+			Headers: envelope.Headers(),
+		})
+	}, append(publishRetryOptions, retry.Context(ctx))...); err != nil {
+		s.log.WarnMsg("publishCommandResult.PublishMessage", err)
+		return
+	}
+
+	s.metrics.CommandResultKafkaMessages.Inc()
 }
 
 func (s *productMessageProcessor) logProcessMessage(m kafka.Message, workerID int) {
@@ -0,0 +1,44 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/aggregate"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
+	"github.com/opentracing/opentracing-go"
+)
+
+type GetProductAtVersionHandler interface {
+	Handle(ctx context.Context, query *GetProductAtVersionQuery) (*aggregate.ProductAggregate, error)
+}
+
+type getProductAtVersionHandler struct {
+	log        logger.Logger
+	cfg        *config.Config
+	eventStore repository.EventStore
+}
+
+func NewGetProductAtVersionHandler(log logger.Logger, cfg *config.Config, eventStore repository.EventStore) *getProductAtVersionHandler {
+	return &getProductAtVersionHandler{log: log, cfg: cfg, eventStore: eventStore}
+}
+
+func (q *getProductAtVersionHandler) Handle(ctx context.Context, query *GetProductAtVersionQuery) (*aggregate.ProductAggregate, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "getProductAtVersionHandler.Handle")
+	defer span.Finish()
+
+	version := query.Version
+	if version == 0 && !query.At.IsZero() {
+		versionAt, err := q.eventStore.GetVersionAt(ctx, query.ProductID, query.At)
+		if err != nil {
+			return nil, err
+		}
+		if versionAt == 0 {
+			return nil, aggregate.ErrAggregateNotFound
+		}
+		version = versionAt
+	}
+
+	return aggregate.Load(ctx, q.eventStore, query.ProductID, version)
+}
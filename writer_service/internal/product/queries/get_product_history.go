@@ -0,0 +1,41 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/aggregate"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
+	"github.com/opentracing/opentracing-go"
+)
+
+type GetProductHistoryHandler interface {
+	Handle(ctx context.Context, query *GetProductHistoryQuery) ([]*models.ProductEvent, error)
+}
+
+type getProductHistoryHandler struct {
+	log        logger.Logger
+	cfg        *config.Config
+	eventStore repository.EventStore
+}
+
+func NewGetProductHistoryHandler(log logger.Logger, cfg *config.Config, eventStore repository.EventStore) *getProductHistoryHandler {
+	return &getProductHistoryHandler{log: log, cfg: cfg, eventStore: eventStore}
+}
+
+func (q *getProductHistoryHandler) Handle(ctx context.Context, query *GetProductHistoryQuery) ([]*models.ProductEvent, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "getProductHistoryHandler.Handle")
+	defer span.Finish()
+
+	events, err := q.eventStore.LoadEvents(ctx, query.ProductID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, aggregate.ErrAggregateNotFound
+	}
+
+	return events, nil
+}
@@ -1,13 +1,30 @@
 package queries
 
-import uuid "github.com/satori/go.uuid"
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
 
 type ProductQueries struct {
-	GetProductById GetProductByIdHandler
+	GetProductById      GetProductByIdHandler
+	GetProductAtVersion GetProductAtVersionHandler
+	GetProductHistory   GetProductHistoryHandler
+	StreamProducts      StreamProductsHandler
 }
 
-func NewProductQueries(getProductById GetProductByIdHandler) *ProductQueries {
-	return &ProductQueries{GetProductById: getProductById}
+func NewProductQueries(
+	getProductById GetProductByIdHandler,
+	getProductAtVersion GetProductAtVersionHandler,
+	getProductHistory GetProductHistoryHandler,
+	streamProducts StreamProductsHandler,
+) *ProductQueries {
+	return &ProductQueries{
+		GetProductById:      getProductById,
+		GetProductAtVersion: getProductAtVersion,
+		GetProductHistory:   getProductHistory,
+		StreamProducts:      streamProducts,
+	}
 }
 
 type GetProductByIdQuery struct {
@@ -17,3 +34,31 @@ type GetProductByIdQuery struct {
 func NewGetProductByIdQuery(productID uuid.UUID) *GetProductByIdQuery {
 	return &GetProductByIdQuery{ProductID: productID}
 }
+
+// GetProductAtVersionQuery Version 0 with zero At means the latest version
+type GetProductAtVersionQuery struct {
+	ProductID uuid.UUID `json:"productId" validate:"required"`
+	Version   int64     `json:"version" validate:"gte=0"`
+	At        time.Time `json:"at"`
+}
+
+func NewGetProductAtVersionQuery(productID uuid.UUID, version int64, at time.Time) *GetProductAtVersionQuery {
+	return &GetProductAtVersionQuery{ProductID: productID, Version: version, At: at}
+}
+
+type GetProductHistoryQuery struct {
+	ProductID uuid.UUID `json:"productId" validate:"required"`
+}
+
+func NewGetProductHistoryQuery(productID uuid.UUID) *GetProductHistoryQuery {
+	return &GetProductHistoryQuery{ProductID: productID}
+}
+
+// StreamProductsQuery BatchSize 0 means the default batch size
+type StreamProductsQuery struct {
+	BatchSize int `json:"batchSize" validate:"gte=0,lte=1000"`
+}
+
+func NewStreamProductsQuery(batchSize int) *StreamProductsQuery {
+	return &StreamProductsQuery{BatchSize: batchSize}
+}
@@ -0,0 +1,41 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	defaultStreamBatchSize = 500
+)
+
+type StreamProductsHandler interface {
+	Handle(ctx context.Context, query *StreamProductsQuery, fn func(products []*models.Product) error) error
+}
+
+type streamProductsHandler struct {
+	log    logger.Logger
+	cfg    *config.Config
+	pgRepo repository.Repository
+}
+
+func NewStreamProductsHandler(log logger.Logger, cfg *config.Config, pgRepo repository.Repository) *streamProductsHandler {
+	return &streamProductsHandler{log: log, cfg: cfg, pgRepo: pgRepo}
+}
+
+func (q *streamProductsHandler) Handle(ctx context.Context, query *StreamProductsQuery, fn func(products []*models.Product) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "streamProductsHandler.Handle")
+	defer span.Finish()
+
+	batchSize := query.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultStreamBatchSize
+	}
+
+	return q.pgRepo.StreamProducts(ctx, batchSize, fn)
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
+	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+type eventStore struct {
+	log logger.Logger
+	cfg *config.Config
+	db  *pgxpool.Pool
+}
+
+func NewEventStore(log logger.Logger, cfg *config.Config, db *pgxpool.Pool) *eventStore {
+	return &eventStore{log: log, cfg: cfg, db: db}
+}
+
+// AppendEvent stores event with the next aggregate version, concurrent appends of the same version fail on the primary key
+func (e *eventStore) AppendEvent(ctx context.Context, aggregateID uuid.UUID, eventType string, data []byte) (*models.ProductEvent, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "eventStore.AppendEvent")
+	defer span.Finish()
+
+	var event models.ProductEvent
+	if err := postgres.GetQuerier(ctx, e.db).QueryRow(ctx, appendProductEventQuery, aggregateID, eventType, data).Scan(
+		&event.AggregateID,
+		&event.Version,
+		&event.EventType,
+		&event.Data,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRow")
+	}
+
+	return &event, nil
+}
+
+// LoadEvents returns aggregate events with afterVersion < version <= toVersion, toVersion 0 means up to the latest
+func (e *eventStore) LoadEvents(ctx context.Context, aggregateID uuid.UUID, afterVersion int64, toVersion int64) ([]*models.ProductEvent, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "eventStore.LoadEvents")
+	defer span.Finish()
+
+	rows, err := postgres.GetQuerier(ctx, e.db).Query(ctx, getProductEventsQuery, aggregateID, afterVersion, toVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "Query")
+	}
+	defer rows.Close()
+
+	events := make([]*models.ProductEvent, 0)
+	for rows.Next() {
+		var event models.ProductEvent
+		if err := rows.Scan(&event.AggregateID, &event.Version, &event.EventType, &event.Data, &event.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows.Err")
+	}
+
+	return events, nil
+}
+
+// GetVersionAt returns the latest aggregate version created at or before at, 0 when there is none
+func (e *eventStore) GetVersionAt(ctx context.Context, aggregateID uuid.UUID, at time.Time) (int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "eventStore.GetVersionAt")
+	defer span.Finish()
+
+	var version int64
+	if err := postgres.GetQuerier(ctx, e.db).QueryRow(ctx, getProductVersionAtQuery, aggregateID, at).Scan(&version); err != nil {
+		return 0, errors.Wrap(err, "Scan")
+	}
+
+	return version, nil
+}
+
+func (e *eventStore) SaveSnapshot(ctx context.Context, snapshot *models.ProductSnapshot) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "eventStore.SaveSnapshot")
+	defer span.Finish()
+
+	if _, err := postgres.GetQuerier(ctx, e.db).Exec(ctx, saveProductSnapshotQuery, snapshot.AggregateID, snapshot.Version, snapshot.Data); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+
+	return nil
+}
+
+// GetSnapshot returns the latest snapshot with version <= maxVersion (0 means any), nil when there is none
+func (e *eventStore) GetSnapshot(ctx context.Context, aggregateID uuid.UUID, maxVersion int64) (*models.ProductSnapshot, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "eventStore.GetSnapshot")
+	defer span.Finish()
+
+	var snapshot models.ProductSnapshot
+	if err := postgres.GetQuerier(ctx, e.db).QueryRow(ctx, getProductSnapshotQuery, aggregateID, maxVersion).Scan(
+		&snapshot.AggregateID,
+		&snapshot.Version,
+		&snapshot.Data,
+		&snapshot.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "Scan")
+	}
+
+	return &snapshot, nil
+}
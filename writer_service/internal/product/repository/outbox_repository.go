@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
+	"github.com/herhu/Microservices-PR/writer_service/config"
+	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// outboxRelayLockKey advisory lock key held by the instance relaying the outbox
+const outboxRelayLockKey int64 = 0x6f7574626f78
+
+type outboxRepository struct {
+	log logger.Logger
+	cfg *config.Config
+	db  *pgxpool.Pool
+}
+
+func NewOutboxRepository(log logger.Logger, cfg *config.Config, db *pgxpool.Pool) *outboxRepository {
+	return &outboxRepository{log: log, cfg: cfg, db: db}
+}
+
+func (o *outboxRepository) SaveMessage(ctx context.Context, msg *models.OutboxMessage) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.SaveMessage")
+	defer span.Finish()
+
+	headersBytes, err := json.Marshal(msg.Headers)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	if _, err := postgres.GetQuerier(ctx, o.db).Exec(ctx, createOutboxMessageQuery, msg.AggregateID, msg.Topic, msg.Payload, headersBytes); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+
+	return nil
+}
+
+// TryLockRelay takes the relay lock until the transaction ends, returns false when another instance holds it
+func (o *outboxRepository) TryLockRelay(ctx context.Context) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.TryLockRelay")
+	defer span.Finish()
+
+	var locked bool
+	if err := postgres.GetQuerier(ctx, o.db).QueryRow(ctx, tryLockOutboxRelayQuery, outboxRelayLockKey).Scan(&locked); err != nil {
+		return false, errors.Wrap(err, "Scan")
+	}
+
+	return locked, nil
+}
+
+func (o *outboxRepository) GetPendingMessages(ctx context.Context, limit int) ([]*models.OutboxMessage, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.GetPendingMessages")
+	defer span.Finish()
+
+	rows, err := postgres.GetQuerier(ctx, o.db).Query(ctx, getPendingOutboxMessagesQuery, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "Query")
+	}
+	defer rows.Close()
+
+	messages := make([]*models.OutboxMessage, 0, limit)
+	for rows.Next() {
+		var msg models.OutboxMessage
+		var headersBytes []byte
+		if err := rows.Scan(&msg.ID, &msg.AggregateID, &msg.Topic, &msg.Payload, &headersBytes, &msg.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		if err := json.Unmarshal(headersBytes, &msg.Headers); err != nil {
+			return nil, errors.Wrap(err, "json.Unmarshal")
+		}
+		messages = append(messages, &msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows.Err")
+	}
+
+	return messages, nil
+}
+
+func (o *outboxRepository) MarkSent(ctx context.Context, ids []int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.MarkSent")
+	defer span.Finish()
+
+	if _, err := postgres.GetQuerier(ctx, o.db).Exec(ctx, markOutboxMessagesSentQuery, ids); err != nil {
+		return errors.Wrap(err, "Exec")
+	}
+
+	return nil
+}
+
+func (o *outboxRepository) GetPendingStats(ctx context.Context) (time.Duration, int64, error) {
+	var lagSeconds float64
+	var pending int64
+	if err := postgres.GetQuerier(ctx, o.db).QueryRow(ctx, getOutboxPendingStatsQuery).Scan(&lagSeconds, &pending); err != nil {
+		return 0, 0, errors.Wrap(err, "Scan")
+	}
+
+	return time.Duration(lagSeconds * float64(time.Second)), pending, nil
+}
+
+func (o *outboxRepository) DeleteSentMessages(ctx context.Context, retentionHours int) (int64, error) {
+	tag, err := postgres.GetQuerier(ctx, o.db).Exec(ctx, deleteSentOutboxMessagesQuery, retentionHours)
+	if err != nil {
+		return 0, errors.Wrap(err, "Exec")
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
 	"github.com/herhu/Microservices-PR/writer_service/config"
 	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -22,38 +24,48 @@ func NewProductRepository(log logger.Logger, cfg *config.Config, db *pgxpool.Poo
 	return &productRepository{log: log, cfg: cfg, db: db}
 }
 
+// CreateProduct returns ErrProductExists without writing when a product of the same id is stored
 func (p *productRepository) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "productRepository.CreateProduct")
 	defer span.Finish()
 
 	var created models.Product
-	if err := p.db.QueryRow(ctx, createProductQuery, &product.ProductID, &product.Name, &product.Description, &product.Price).Scan(
+	if err := postgres.GetQuerier(ctx, p.db).QueryRow(ctx, createProductQuery, &product.ProductID, &product.Name, &product.Description, &product.Price).Scan(
 		&created.ProductID,
 		&created.Name,
 		&created.Description,
 		&created.Price,
 		&created.CreatedAt,
 		&created.UpdatedAt,
+		&created.Version,
 	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductExists
+		}
 		return nil, errors.Wrap(err, "db.QueryRow")
 	}
 
 	return &created, nil
 }
 
+// UpdateProduct product.Version is the expected current version, 0 skips the check
 func (p *productRepository) UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "productRepository.UpdateProduct")
 	defer span.Finish()
 
 	var prod models.Product
-	if err := p.db.QueryRow(
+	if err := postgres.GetQuerier(ctx, p.db).QueryRow(
 		ctx,
 		updateProductQuery,
 		&product.Name,
 		&product.Description,
 		&product.Price,
 		&product.ProductID,
-	).Scan(&prod.ProductID, &prod.Name, &prod.Description, &prod.Price, &prod.CreatedAt, &prod.UpdatedAt); err != nil {
+		&product.Version,
+	).Scan(&prod.ProductID, &prod.Name, &prod.Description, &prod.Price, &prod.CreatedAt, &prod.UpdatedAt, &prod.Version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) && product.Version != 0 {
+			return nil, p.versionConflictErr(ctx, product.ProductID, product.Version, err)
+		}
 		return nil, errors.Wrap(err, "Scan")
 	}
 
@@ -65,13 +77,14 @@ func (p *productRepository) GetProductById(ctx context.Context, uuid uuid.UUID)
 	defer span.Finish()
 
 	var product models.Product
-	if err := p.db.QueryRow(ctx, getProductByIdQuery, uuid).Scan(
+	if err := postgres.GetQuerier(ctx, p.db).QueryRow(ctx, getProductByIdQuery, uuid).Scan(
 		&product.ProductID,
 		&product.Name,
 		&product.Description,
 		&product.Price,
 		&product.CreatedAt,
 		&product.UpdatedAt,
+		&product.Version,
 	); err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
@@ -79,14 +92,75 @@ func (p *productRepository) GetProductById(ctx context.Context, uuid uuid.UUID)
 	return &product, nil
 }
 
-func (p *productRepository) DeleteProductByID(ctx context.Context, uuid uuid.UUID) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "productRepository.DeleteProductByID")
+// StreamProducts passes all products to fn in batches of batchSize ordered by id
+func (p *productRepository) StreamProducts(ctx context.Context, batchSize int, fn func(products []*models.Product) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "productRepository.StreamProducts")
 	defer span.Finish()
 
-	_, err := p.db.Exec(ctx, deleteProductByIdQuery, uuid)
+	rows, err := postgres.GetQuerier(ctx, p.db).Query(ctx, streamProductsQuery)
 	if err != nil {
-		return errors.Wrap(err, "Exec")
+		return errors.Wrap(err, "Query")
+	}
+	defer rows.Close()
+
+	batch := make([]*models.Product, 0, batchSize)
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(
+			&product.ProductID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+			&product.Version,
+		); err != nil {
+			return errors.Wrap(err, "Scan")
+		}
+
+		batch = append(batch, &product)
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]*models.Product, 0, batchSize)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "rows.Err")
 	}
 
+	if len(batch) > 0 {
+		return fn(batch)
+	}
 	return nil
 }
+
+// DeleteProductByID returns the version of the deleted product, ErrProductNotFound when it did not exist
+func (p *productRepository) DeleteProductByID(ctx context.Context, uuid uuid.UUID) (int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "productRepository.DeleteProductByID")
+	defer span.Finish()
+
+	var version int64
+	if err := postgres.GetQuerier(ctx, p.db).QueryRow(ctx, deleteProductByIdQuery, uuid).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrProductNotFound
+		}
+		return 0, errors.Wrap(err, "Scan")
+	}
+
+	return version, nil
+}
+
+func (p *productRepository) versionConflictErr(ctx context.Context, productID uuid.UUID, expectedVersion int64, noRowsErr error) error {
+	var currentVersion int64
+	if err := postgres.GetQuerier(ctx, p.db).QueryRow(ctx, getProductVersionQuery, productID).Scan(&currentVersion); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.Wrap(noRowsErr, "Scan")
+		}
+		return errors.Wrap(err, "Scan")
+	}
+
+	return errors.Wrapf(ErrVersionConflict, "expected version %d, current version %d", expectedVersion, currentVersion)
+}
@@ -2,15 +2,41 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/herhu/Microservices-PR/writer_service/internal/models"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
+var (
+	ErrVersionConflict = errors.New("product version conflict")
+	ErrProductNotFound = errors.New("product not found")
+	ErrProductExists   = errors.New("product already exists")
+)
+
 type Repository interface {
 	CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
 	UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
-	DeleteProductByID(ctx context.Context, uuid uuid.UUID) error
+	DeleteProductByID(ctx context.Context, uuid uuid.UUID) (int64, error)
 
 	GetProductById(ctx context.Context, uuid uuid.UUID) (*models.Product, error)
+	StreamProducts(ctx context.Context, batchSize int, fn func(products []*models.Product) error) error
+}
+
+type OutboxRepository interface {
+	SaveMessage(ctx context.Context, msg *models.OutboxMessage) error
+	TryLockRelay(ctx context.Context) (bool, error)
+	GetPendingMessages(ctx context.Context, limit int) ([]*models.OutboxMessage, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	GetPendingStats(ctx context.Context) (lag time.Duration, pending int64, err error)
+	DeleteSentMessages(ctx context.Context, retentionHours int) (int64, error)
+}
+
+type EventStore interface {
+	AppendEvent(ctx context.Context, aggregateID uuid.UUID, eventType string, data []byte) (*models.ProductEvent, error)
+	LoadEvents(ctx context.Context, aggregateID uuid.UUID, afterVersion int64, toVersion int64) ([]*models.ProductEvent, error)
+	GetVersionAt(ctx context.Context, aggregateID uuid.UUID, at time.Time) (int64, error)
+	SaveSnapshot(ctx context.Context, snapshot *models.ProductSnapshot) error
+	GetSnapshot(ctx context.Context, aggregateID uuid.UUID, maxVersion int64) (*models.ProductSnapshot, error)
 }
@@ -1,19 +1,55 @@
 package repository
 
 const (
-	createProductQuery = `INSERT INTO products (product_id, name, description, price, created_at, updated_at) 
-	VALUES ($1, $2, $3, $4, now(), now()) RETURNING product_id, name, description, price, created_at, updated_at`
+	createProductQuery = `INSERT INTO products (product_id, name, description, price, created_at, updated_at, version) 
+	VALUES ($1, $2, $3, $4, now(), now(), 1) ON CONFLICT (product_id) DO NOTHING RETURNING product_id, name, description, price, created_at, updated_at, version`
 
 	updateProductQuery = `UPDATE products p SET 
                       name=COALESCE(NULLIF($1, ''), name), 
                       description=COALESCE(NULLIF($2, ''), description), 
                       price=COALESCE(NULLIF($3, 0), price),
-                      updated_at = now()
-                      WHERE product_id=$4
-                      RETURNING product_id, name, description, price, created_at, updated_at`
+                      updated_at = now(),
+                      version = version + 1
+                      WHERE product_id=$4 AND ($5::BIGINT = 0 OR version = $5::BIGINT)
+                      RETURNING product_id, name, description, price, created_at, updated_at, version`
 
-	getProductByIdQuery = `SELECT p.product_id, p.name, p.description, p.price, p.created_at, p.updated_at 
+	getProductByIdQuery = `SELECT p.product_id, p.name, p.description, p.price, p.created_at, p.updated_at, p.version 
 	FROM products p WHERE p.product_id = $1`
 
-	deleteProductByIdQuery = `DELETE FROM products WHERE product_id = $1`
+	streamProductsQuery = `SELECT p.product_id, p.name, p.description, p.price, p.created_at, p.updated_at, p.version 
+	FROM products p ORDER BY p.product_id`
+
+	getProductVersionQuery = `SELECT p.version FROM products p WHERE p.product_id = $1`
+
+	deleteProductByIdQuery = `DELETE FROM products WHERE product_id = $1 RETURNING version`
+
+	createOutboxMessageQuery = `INSERT INTO outbox (aggregate_id, topic, payload, headers, created_at) 
+	VALUES ($1, $2, $3, $4, now())`
+
+	tryLockOutboxRelayQuery = `SELECT pg_try_advisory_xact_lock($1)`
+
+	getPendingOutboxMessagesQuery = `SELECT o.id, o.aggregate_id, o.topic, o.payload, o.headers, o.created_at 
+	FROM outbox o WHERE o.sent_at IS NULL ORDER BY o.id LIMIT $1 FOR UPDATE`
+
+	markOutboxMessagesSentQuery = `UPDATE outbox SET sent_at = now() WHERE id = ANY($1)`
+
+	getOutboxPendingStatsQuery = `SELECT COALESCE(EXTRACT(EPOCH FROM now() - MIN(o.created_at)), 0), COUNT(*) 
+	FROM outbox o WHERE o.sent_at IS NULL`
+
+	deleteSentOutboxMessagesQuery = `DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at < now() - make_interval(hours => $1)`
+
+	appendProductEventQuery = `INSERT INTO product_events (aggregate_id, version, event_type, data, created_at) 
+	VALUES ($1, (SELECT COALESCE(MAX(e.version), 0) + 1 FROM product_events e WHERE e.aggregate_id = $1), $2, $3, now()) 
+	RETURNING aggregate_id, version, event_type, data, created_at`
+
+	getProductEventsQuery = `SELECT e.aggregate_id, e.version, e.event_type, e.data, e.created_at 
+	FROM product_events e WHERE e.aggregate_id = $1 AND e.version > $2 AND ($3::BIGINT = 0 OR e.version <= $3::BIGINT) ORDER BY e.version`
+
+	getProductVersionAtQuery = `SELECT COALESCE(MAX(e.version), 0) FROM product_events e WHERE e.aggregate_id = $1 AND e.created_at <= $2`
+
+	saveProductSnapshotQuery = `INSERT INTO product_snapshots (aggregate_id, version, data, created_at) 
+	VALUES ($1, $2, $3, now()) ON CONFLICT (aggregate_id, version) DO NOTHING`
+
+	getProductSnapshotQuery = `SELECT s.aggregate_id, s.version, s.data, s.created_at 
+	FROM product_snapshots s WHERE s.aggregate_id = $1 AND ($2::BIGINT = 0 OR s.version <= $2::BIGINT) ORDER BY s.version DESC LIMIT 1`
 )
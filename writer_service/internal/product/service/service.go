@@ -1,8 +1,8 @@
 package service
 
 import (
-	kafkaClient "github.com/herhu/Microservices-PR/pkg/kafka"
 	"github.com/herhu/Microservices-PR/pkg/logger"
+	"github.com/herhu/Microservices-PR/pkg/postgres"
 	"github.com/herhu/Microservices-PR/writer_service/config"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/commands"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/queries"
@@ -14,16 +14,19 @@ type ProductService struct {
 	Queries  *queries.ProductQueries
 }
 
-func NewProductService(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, kafkaProducer kafkaClient.Producer) *ProductService {
+func NewProductService(log logger.Logger, cfg *config.Config, pgRepo repository.Repository, outboxRepo repository.OutboxRepository, eventStore repository.EventStore, transactor postgres.Transactor) *ProductService {
 
-	updateProductHandler := commands.NewUpdateProductHandler(log, cfg, pgRepo, kafkaProducer)
-	createProductHandler := commands.NewCreateProductHandler(log, cfg, pgRepo, kafkaProducer)
-	deleteProductHandler := commands.NewDeleteProductHandler(log, cfg, pgRepo, kafkaProducer)
+	updateProductHandler := commands.NewUpdateProductHandler(log, cfg, pgRepo, outboxRepo, eventStore, transactor)
+	createProductHandler := commands.NewCreateProductHandler(log, cfg, pgRepo, outboxRepo, eventStore, transactor)
+	deleteProductHandler := commands.NewDeleteProductHandler(log, cfg, pgRepo, outboxRepo, eventStore, transactor)
 
 	getProductByIdHandler := queries.NewGetProductByIdHandler(log, cfg, pgRepo)
+	getProductAtVersionHandler := queries.NewGetProductAtVersionHandler(log, cfg, eventStore)
+	getProductHistoryHandler := queries.NewGetProductHistoryHandler(log, cfg, eventStore)
+	streamProductsHandler := queries.NewStreamProductsHandler(log, cfg, pgRepo)
 
 	productCommands := commands.NewProductCommands(createProductHandler, updateProductHandler, deleteProductHandler)
-	productQueries := queries.NewProductQueries(getProductByIdHandler)
+	productQueries := queries.NewProductQueries(getProductByIdHandler, getProductAtVersionHandler, getProductHistoryHandler, streamProductsHandler)
 
 	return &ProductService{Commands: productCommands, Queries: productQueries}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/go-playground/validator"
@@ -14,24 +15,25 @@ import (
 	"github.com/herhu/Microservices-PR/pkg/tracing"
 	"github.com/herhu/Microservices-PR/writer_service/config"
 	"github.com/herhu/Microservices-PR/writer_service/internal/metrics"
+	"github.com/herhu/Microservices-PR/writer_service/internal/outbox"
 	kafkaConsumer "github.com/herhu/Microservices-PR/writer_service/internal/product/delivery/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/repository"
 	"github.com/herhu/Microservices-PR/writer_service/internal/product/service"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
-	"github.com/segmentio/kafka-go"
 )
 
 type server struct {
-	log       logger.Logger
-	cfg       *config.Config
-	v         *validator.Validate
-	kafkaConn *kafka.Conn
-	ps        *service.ProductService
-	im        interceptors.InterceptorManager
-	pgConn    *pgxpool.Pool
-	metrics   *metrics.WriterServiceMetrics
+	log           logger.Logger
+	cfg           *config.Config
+	v             *validator.Validate
+	broker        kafkaClient.Broker
+	consumerGroup kafkaClient.ConsumerGroup
+	ps            *service.ProductService
+	im            interceptors.InterceptorManager
+	pgConn        *pgxpool.Pool
+	metrics       *metrics.WriterServiceMetrics
 }
 
 func NewServer(log logger.Logger, cfg *config.Config) *server {
@@ -53,16 +55,39 @@ func (s *server) Run() error {
 	s.log.Infof("postgres connected: %v", pgxConn.Stat().TotalConns())
 	defer pgxConn.Close()
 
-	kafkaProducer := kafkaClient.NewProducer(s.log, s.cfg.Kafka.Brokers)
+	if err := s.connectKafkaBrokers(ctx); err != nil {
+		return errors.Wrap(err, "s.connectKafkaBrokers")
+	}
+	defer s.broker.Close() // nolint: errcheck
+
+	if s.cfg.Kafka.InitTopics {
+		s.initKafkaTopics(ctx)
+	}
+
+	kafkaProducer := s.broker.NewProducer()
 	defer kafkaProducer.Close() // nolint: errcheck
 
+	transactor := postgres.NewTransactor(pgxConn)
 	productRepo := repository.NewProductRepository(s.log, s.cfg, pgxConn)
-	s.ps = service.NewProductService(s.log, s.cfg, productRepo, kafkaProducer)
-	productMessageProcessor := kafkaConsumer.NewProductMessageProcessor(s.log, s.cfg, s.v, s.ps, s.metrics)
+	outboxRepo := repository.NewOutboxRepository(s.log, s.cfg, pgxConn)
+	eventStore := repository.NewEventStore(s.log, s.cfg, pgxConn)
+	s.ps = service.NewProductService(s.log, s.cfg, productRepo, outboxRepo, eventStore, transactor)
+	productMessageProcessor := kafkaConsumer.NewProductMessageProcessor(s.log, s.cfg, s.v, s.ps, s.metrics, kafkaProducer)
 
 	s.log.Info("Starting Writer Kafka consumers")
-	cg := kafkaClient.NewConsumerGroup(s.cfg.Kafka.Brokers, s.cfg.Kafka.GroupID, s.log)
-	go cg.ConsumeTopic(ctx, s.getConsumerGroupTopics(), kafkaConsumer.PoolSize, productMessageProcessor.ProcessMessages)
+	s.consumerGroup = kafkaClient.NewConsumerGroup(s.broker, s.cfg.Kafka, s.log)
+	retryRedeliverer := kafkaClient.NewRetryRedeliverer(s.log, s.cfg.Kafka, s.broker, s.getConsumerGroupTopics(), kafkaProducer)
+
+	consumersWg := &sync.WaitGroup{}
+	consumersWg.Add(2)
+	go func() {
+		defer consumersWg.Done()
+		s.consumerGroup.ConsumeTopic(ctx, s.getConsumerGroupTopics(), kafkaConsumer.PoolSize, productMessageProcessor.ProcessMessage)
+	}()
+	go func() {
+		defer consumersWg.Done()
+		retryRedeliverer.Run(ctx)
+	}()
 
 	closeGrpcServer, grpcServer, err := s.newWriterGrpcServer()
 	if err != nil {
@@ -70,14 +95,12 @@ func (s *server) Run() error {
 	}
 	defer closeGrpcServer() // nolint: errcheck
 
-	if err := s.connectKafkaBrokers(ctx); err != nil {
-		return errors.Wrap(err, "s.connectKafkaBrokers")
-	}
-	defer s.kafkaConn.Close() // nolint: errcheck
-
-	if s.cfg.Kafka.InitTopics {
-		s.initKafkaTopics(ctx)
-	}
+	outboxRelay := outbox.NewRelay(s.log, s.cfg, transactor, outboxRepo, kafkaProducer, s.metrics)
+	consumersWg.Add(1)
+	go func() {
+		defer consumersWg.Done()
+		outboxRelay.Run(ctx)
+	}()
 
 	s.runHealthCheck(ctx)
 	s.runMetrics(cancel)
@@ -94,5 +117,8 @@ func (s *server) Run() error {
 	<-ctx.Done()
 	grpcServer.GracefulStop()
 
+	s.log.Info("Waiting for Kafka consumers to drain and outbox relay to stop")
+	consumersWg.Wait()
+
 	return nil
 }
@@ -2,9 +2,7 @@ package server
 
 import (
 	"context"
-	"net"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/heptiolabs/healthcheck"
@@ -22,42 +20,22 @@ const (
 )
 
 func (s *server) connectKafkaBrokers(ctx context.Context) error {
-	kafkaConn, err := kafkaClient.NewKafkaConn(ctx, s.cfg.Kafka)
+	broker, err := kafkaClient.NewBroker(s.log, s.cfg.Kafka)
 	if err != nil {
-		return errors.Wrap(err, "kafka.NewKafkaCon")
+		return errors.Wrap(err, "kafka.NewBroker")
 	}
 
-	s.kafkaConn = kafkaConn
-
-	brokers, err := kafkaConn.Brokers()
-	if err != nil {
-		return errors.Wrap(err, "kafkaConn.Brokers")
+	if err := broker.Ping(ctx); err != nil {
+		return errors.Wrap(err, "broker.Ping")
 	}
 
-	s.log.Infof("kafka connected to brokers: %+v", brokers)
+	s.broker = broker
+	s.log.Infof("kafka connected, driver: %s, brokers: %+v", s.cfg.Kafka.Driver, s.cfg.Kafka.Brokers)
 
 	return nil
 }
 
 func (s *server) initKafkaTopics(ctx context.Context) {
-	controller, err := s.kafkaConn.Controller()
-	if err != nil {
-		s.log.WarnMsg("kafkaConn.Controller", err)
-		return
-	}
-
-	controllerURI := net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port))
-	s.log.Infof("kafka controller uri: %s", controllerURI)
-
-	conn, err := kafka.DialContext(ctx, "tcp", controllerURI)
-	if err != nil {
-		s.log.WarnMsg("initKafkaTopics.DialContext", err)
-		return
-	}
-	defer conn.Close() // nolint: errcheck
-
-	s.log.Infof("established new kafka controller connection: %s", controllerURI)
-
 	productCreateTopic := kafka.TopicConfig{
 		Topic:             s.cfg.KafkaTopics.ProductCreate.TopicName,
 		NumPartitions:     s.cfg.KafkaTopics.ProductCreate.Partitions,
@@ -94,19 +72,50 @@ func (s *server) initKafkaTopics(ctx context.Context) {
 		ReplicationFactor: s.cfg.KafkaTopics.ProductDeleted.ReplicationFactor,
 	}
 
-	if err := conn.CreateTopics(
+	productCreateDLQTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.ProductCreateDLQ.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.ProductCreateDLQ.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.ProductCreateDLQ.ReplicationFactor,
+	}
+
+	productUpdateDLQTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.ProductUpdateDLQ.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.ProductUpdateDLQ.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.ProductUpdateDLQ.ReplicationFactor,
+	}
+
+	productDeleteDLQTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.ProductDeleteDLQ.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.ProductDeleteDLQ.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.ProductDeleteDLQ.ReplicationFactor,
+	}
+
+	commandResultTopic := kafka.TopicConfig{
+		Topic:             s.cfg.KafkaTopics.CommandResult.TopicName,
+		NumPartitions:     s.cfg.KafkaTopics.CommandResult.Partitions,
+		ReplicationFactor: s.cfg.KafkaTopics.CommandResult.ReplicationFactor,
+	}
+
+	topics := []kafka.TopicConfig{
 		productCreateTopic,
 		productUpdateTopic,
 		productCreatedTopic,
 		productUpdatedTopic,
 		productDeleteTopic,
 		productDeletedTopic,
-	); err != nil {
-		s.log.WarnMsg("kafkaConn.CreateTopics", err)
+		productCreateDLQTopic,
+		productUpdateDLQTopic,
+		productDeleteDLQTopic,
+		commandResultTopic,
+	}
+	topics = append(topics, s.cfg.Kafka.Retry.TopicConfigs(s.getConsumerGroupTopics())...)
+
+	if err := s.broker.CreateTopics(ctx, topics...); err != nil {
+		s.log.WarnMsg("broker.CreateTopics", err)
 		return
 	}
 
-	s.log.Infof("kafka topics created or already exists: %+v", []kafka.TopicConfig{productCreateTopic, productUpdateTopic, productCreatedTopic, productUpdatedTopic, productDeleteTopic, productDeletedTopic})
+	s.log.Infof("kafka topics created or already exists: %+v", topics)
 }
 
 func (s *server) getConsumerGroupTopics() []string {
@@ -129,13 +138,11 @@ func (s *server) runHealthCheck(ctx context.Context) {
 	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
 
 	health.AddReadinessCheck(constants.Kafka, healthcheck.AsyncWithContext(ctx, func() error {
-		_, err := s.kafkaConn.Brokers()
-		if err != nil {
-			return err
-		}
-		return nil
+		return s.broker.Ping(ctx)
 	}, time.Duration(s.cfg.Probes.CheckIntervalSeconds)*time.Second))
 
+	health.AddReadinessCheck(constants.KafkaConsumer, s.consumerGroup.Ready)
+
 	go func() {
 		s.log.Infof("Writer microservice Kubernetes probes listening on port: %s", s.cfg.Probes.Port)
 		if err := http.ListenAndServe(s.cfg.Probes.Port, health); err != nil {
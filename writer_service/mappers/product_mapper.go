@@ -1,6 +1,8 @@
 package mappers
 
 import (
+	"encoding/json"
+
 	kafkaMessages "github.com/herhu/Microservices-PR/proto/kafka"
 	"github.com/herhu/Microservices-PR/writer_service/internal/models"
 	writerService "github.com/herhu/Microservices-PR/writer_service/proto/product_writer"
@@ -16,6 +18,7 @@ func ProductToGrpcMessage(product *models.Product) *kafkaMessages.Product {
 		Price:       product.Price,
 		CreatedAt:   timestamppb.New(product.CreatedAt),
 		UpdatedAt:   timestamppb.New(product.UpdatedAt),
+		Version:     product.Version,
 	}
 }
 
@@ -33,6 +36,7 @@ func ProductFromGrpcMessage(product *kafkaMessages.Product) (*models.Product, er
 		Price:       product.GetPrice(),
 		CreatedAt:   product.GetCreatedAt().AsTime(),
 		UpdatedAt:   product.GetUpdatedAt().AsTime(),
+		Version:     product.GetVersion(),
 	}, nil
 }
 
@@ -44,5 +48,25 @@ func WriterProductToGrpc(product *models.Product) *writerService.Product {
 		Price:       product.Price,
 		CreatedAt:   timestamppb.New(product.CreatedAt),
 		UpdatedAt:   timestamppb.New(product.UpdatedAt),
+		Version:     product.Version,
+	}
+}
+
+func ProductEventToGrpc(event *models.ProductEvent) (*writerService.ProductEvent, error) {
+	productEvent := &writerService.ProductEvent{
+		ProductID: event.AggregateID.String(),
+		Version:   event.Version,
+		EventType: event.EventType,
+		CreatedAt: timestamppb.New(event.CreatedAt),
 	}
+
+	if event.EventType != models.ProductDeletedEventType {
+		var product models.Product
+		if err := json.Unmarshal(event.Data, &product); err != nil {
+			return nil, err
+		}
+		productEvent.Product = WriterProductToGrpc(&product)
+	}
+
+	return productEvent, nil
 }
@@ -26,7 +26,7 @@ var file_product_writer_proto_rawDesc = []byte{
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53, 0x65,
 	0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x1d, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x5f, 0x77,
 	0x72, 0x69, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x32, 0x8b, 0x02, 0x0a, 0x0d, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53,
+	0x72, 0x6f, 0x74, 0x6f, 0x32, 0xa7, 0x04, 0x0a, 0x0d, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53,
 	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
 	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12, 0x1f, 0x2e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72,
 	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72,
@@ -43,30 +43,60 @@ var file_product_writer_proto_rawDesc = []byte{
 	0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71,
 	0x1a, 0x20, 0x2e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
 	0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52,
-	0x65, 0x73, 0x42, 0x12, 0x5a, 0x10, 0x2e, 0x2f, 0x3b, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x73, 0x12, 0x63, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x41, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x2e, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x41, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x1a, 0x25, 0x2e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x41, 0x74, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x12, 0x5d, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x23, 0x2e, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74,
+	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x1a, 0x23, 0x2e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x12, 0x56, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x12, 0x20, 0x2e, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x20, 0x2e, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x30, 0x01, 0x42, 0x12,
+	0x5a, 0x10, 0x2e, 0x2f, 0x3b, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_product_writer_proto_goTypes = []interface{}{
-	(*CreateProductReq)(nil),  // 0: writerService.CreateProductReq
-	(*UpdateProductReq)(nil),  // 1: writerService.UpdateProductReq
-	(*GetProductByIdReq)(nil), // 2: writerService.GetProductByIdReq
-	(*CreateProductRes)(nil),  // 3: writerService.CreateProductRes
-	(*UpdateProductRes)(nil),  // 4: writerService.UpdateProductRes
-	(*GetProductByIdRes)(nil), // 5: writerService.GetProductByIdRes
+	(*CreateProductReq)(nil),       // 0: writerService.CreateProductReq
+	(*UpdateProductReq)(nil),       // 1: writerService.UpdateProductReq
+	(*GetProductByIdReq)(nil),      // 2: writerService.GetProductByIdReq
+	(*GetProductAtVersionReq)(nil), // 3: writerService.GetProductAtVersionReq
+	(*GetProductHistoryReq)(nil),   // 4: writerService.GetProductHistoryReq
+	(*StreamProductsReq)(nil),      // 5: writerService.StreamProductsReq
+	(*CreateProductRes)(nil),       // 6: writerService.CreateProductRes
+	(*UpdateProductRes)(nil),       // 7: writerService.UpdateProductRes
+	(*GetProductByIdRes)(nil),      // 8: writerService.GetProductByIdRes
+	(*GetProductAtVersionRes)(nil), // 9: writerService.GetProductAtVersionRes
+	(*GetProductHistoryRes)(nil),   // 10: writerService.GetProductHistoryRes
+	(*StreamProductsRes)(nil),      // 11: writerService.StreamProductsRes
 }
 var file_product_writer_proto_depIdxs = []int32{
-	0, // 0: writerService.writerService.CreateProduct:input_type -> writerService.CreateProductReq
-	1, // 1: writerService.writerService.UpdateProduct:input_type -> writerService.UpdateProductReq
-	2, // 2: writerService.writerService.GetProductById:input_type -> writerService.GetProductByIdReq
-	3, // 3: writerService.writerService.CreateProduct:output_type -> writerService.CreateProductRes
-	4, // 4: writerService.writerService.UpdateProduct:output_type -> writerService.UpdateProductRes
-	5, // 5: writerService.writerService.GetProductById:output_type -> writerService.GetProductByIdRes
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: writerService.writerService.CreateProduct:input_type -> writerService.CreateProductReq
+	1,  // 1: writerService.writerService.UpdateProduct:input_type -> writerService.UpdateProductReq
+	2,  // 2: writerService.writerService.GetProductById:input_type -> writerService.GetProductByIdReq
+	3,  // 3: writerService.writerService.GetProductAtVersion:input_type -> writerService.GetProductAtVersionReq
+	4,  // 4: writerService.writerService.GetProductHistory:input_type -> writerService.GetProductHistoryReq
+	5,  // 5: writerService.writerService.StreamProducts:input_type -> writerService.StreamProductsReq
+	6,  // 6: writerService.writerService.CreateProduct:output_type -> writerService.CreateProductRes
+	7,  // 7: writerService.writerService.UpdateProduct:output_type -> writerService.UpdateProductRes
+	8,  // 8: writerService.writerService.GetProductById:output_type -> writerService.GetProductByIdRes
+	9,  // 9: writerService.writerService.GetProductAtVersion:output_type -> writerService.GetProductAtVersionRes
+	10, // 10: writerService.writerService.GetProductHistory:output_type -> writerService.GetProductHistoryRes
+	11, // 11: writerService.writerService.StreamProducts:output_type -> writerService.StreamProductsRes
+	6,  // [6:12] is the sub-list for method output_type
+	0,  // [0:6] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_product_writer_proto_init() }
@@ -20,6 +20,9 @@ type WriterServiceClient interface {
 	CreateProduct(ctx context.Context, in *CreateProductReq, opts ...grpc.CallOption) (*CreateProductRes, error)
 	UpdateProduct(ctx context.Context, in *UpdateProductReq, opts ...grpc.CallOption) (*UpdateProductRes, error)
 	GetProductById(ctx context.Context, in *GetProductByIdReq, opts ...grpc.CallOption) (*GetProductByIdRes, error)
+	GetProductAtVersion(ctx context.Context, in *GetProductAtVersionReq, opts ...grpc.CallOption) (*GetProductAtVersionRes, error)
+	GetProductHistory(ctx context.Context, in *GetProductHistoryReq, opts ...grpc.CallOption) (*GetProductHistoryRes, error)
+	StreamProducts(ctx context.Context, in *StreamProductsReq, opts ...grpc.CallOption) (WriterService_StreamProductsClient, error)
 }
 
 type writerServiceClient struct {
@@ -57,6 +60,56 @@ func (c *writerServiceClient) GetProductById(ctx context.Context, in *GetProduct
 	return out, nil
 }
 
+func (c *writerServiceClient) GetProductAtVersion(ctx context.Context, in *GetProductAtVersionReq, opts ...grpc.CallOption) (*GetProductAtVersionRes, error) {
+	out := new(GetProductAtVersionRes)
+	err := c.cc.Invoke(ctx, "/writerService.writerService/GetProductAtVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *writerServiceClient) GetProductHistory(ctx context.Context, in *GetProductHistoryReq, opts ...grpc.CallOption) (*GetProductHistoryRes, error) {
+	out := new(GetProductHistoryRes)
+	err := c.cc.Invoke(ctx, "/writerService.writerService/GetProductHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *writerServiceClient) StreamProducts(ctx context.Context, in *StreamProductsReq, opts ...grpc.CallOption) (WriterService_StreamProductsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WriterService_serviceDesc.Streams[0], "/writerService.writerService/StreamProducts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &writerServiceStreamProductsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WriterService_StreamProductsClient interface {
+	Recv() (*StreamProductsRes, error)
+	grpc.ClientStream
+}
+
+type writerServiceStreamProductsClient struct {
+	grpc.ClientStream
+}
+
+func (x *writerServiceStreamProductsClient) Recv() (*StreamProductsRes, error) {
+	m := new(StreamProductsRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // WriterServiceServer is the server API for WriterService service.
 // All implementations should embed UnimplementedWriterServiceServer
 // for forward compatibility
@@ -64,6 +117,9 @@ type WriterServiceServer interface {
 	CreateProduct(context.Context, *CreateProductReq) (*CreateProductRes, error)
 	UpdateProduct(context.Context, *UpdateProductReq) (*UpdateProductRes, error)
 	GetProductById(context.Context, *GetProductByIdReq) (*GetProductByIdRes, error)
+	GetProductAtVersion(context.Context, *GetProductAtVersionReq) (*GetProductAtVersionRes, error)
+	GetProductHistory(context.Context, *GetProductHistoryReq) (*GetProductHistoryRes, error)
+	StreamProducts(*StreamProductsReq, WriterService_StreamProductsServer) error
 }
 
 // UnimplementedWriterServiceServer should be embedded to have forward compatible implementations.
@@ -79,6 +135,15 @@ func (UnimplementedWriterServiceServer) UpdateProduct(context.Context, *UpdatePr
 func (UnimplementedWriterServiceServer) GetProductById(context.Context, *GetProductByIdReq) (*GetProductByIdRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetProductById not implemented")
 }
+func (UnimplementedWriterServiceServer) GetProductAtVersion(context.Context, *GetProductAtVersionReq) (*GetProductAtVersionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductAtVersion not implemented")
+}
+func (UnimplementedWriterServiceServer) GetProductHistory(context.Context, *GetProductHistoryReq) (*GetProductHistoryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductHistory not implemented")
+}
+func (UnimplementedWriterServiceServer) StreamProducts(*StreamProductsReq, WriterService_StreamProductsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamProducts not implemented")
+}
 
 // UnsafeWriterServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to WriterServiceServer will
@@ -145,6 +210,63 @@ func _WriterService_GetProductById_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WriterService_GetProductAtVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductAtVersionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WriterServiceServer).GetProductAtVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/writerService.writerService/GetProductAtVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WriterServiceServer).GetProductAtVersion(ctx, req.(*GetProductAtVersionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WriterService_GetProductHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WriterServiceServer).GetProductHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/writerService.writerService/GetProductHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WriterServiceServer).GetProductHistory(ctx, req.(*GetProductHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WriterService_StreamProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamProductsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WriterServiceServer).StreamProducts(m, &writerServiceStreamProductsServer{stream})
+}
+
+type WriterService_StreamProductsServer interface {
+	Send(*StreamProductsRes) error
+	grpc.ServerStream
+}
+
+type writerServiceStreamProductsServer struct {
+	grpc.ServerStream
+}
+
+func (x *writerServiceStreamProductsServer) Send(m *StreamProductsRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _WriterService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "writerService.writerService",
 	HandlerType: (*WriterServiceServer)(nil),
@@ -161,7 +283,21 @@ var _WriterService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetProductById",
 			Handler:    _WriterService_GetProductById_Handler,
 		},
+		{
+			MethodName: "GetProductAtVersion",
+			Handler:    _WriterService_GetProductAtVersion_Handler,
+		},
+		{
+			MethodName: "GetProductHistory",
+			Handler:    _WriterService_GetProductHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamProducts",
+			Handler:       _WriterService_StreamProducts_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "product_writer.proto",
 }